@@ -0,0 +1,210 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	rmGateway "gojo/gateways/rick_and_morty"
+	"gojo/router"
+)
+
+// Config is the service's whole configuration. Load fills it from defaults,
+// an optional file, the environment and command-line flags, in increasing
+// order of precedence.
+type Config struct {
+	Server   ServerConfig
+	Upstream UpstreamConfig
+	Cache    CacheConfig
+	Log      LogConfig
+}
+
+type ServerConfig struct {
+	Addr                string
+	CORSOrigins         []string // Defaults to http://localhost on the listening port; "*" allows any origin, without credentials.
+	ShutdownGracePeriod time.Duration
+	ProblemDetails      bool
+	MaxBatchIDs         int
+	CacheControl        string
+}
+
+type UpstreamConfig struct {
+	BaseURL           string // Defaults to the public rickandmortyapi.com API.
+	Timeout           time.Duration
+	MaxAttempts       int
+	RetryJitter       float64
+	RequestsPerSecond float64 // 0 disables rate limiting.
+	Burst             int
+	FailureThreshold  int // 0 disables the circuit breaker.
+	CoalesceRequests  bool
+}
+
+type CacheConfig struct {
+	Enabled    bool
+	MaxEntries int
+	TTL        time.Duration
+}
+
+type LogConfig struct {
+	Requests bool   // Writes an access log line per request.
+	Output   string // "stderr" or "stdout".
+}
+
+// Default returns the configuration used when nothing overrides it.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Addr:                ":8080",
+			ShutdownGracePeriod: 20 * time.Second,
+			MaxBatchIDs:         200,
+			CacheControl:        "public, max-age=60",
+		},
+		Upstream: UpstreamConfig{
+			Timeout:           10 * time.Second,
+			MaxAttempts:       3,
+			RetryJitter:       0.2,
+			RequestsPerSecond: 10,
+			Burst:             10,
+			FailureThreshold:  5,
+			CoalesceRequests:  true,
+		},
+		Cache: CacheConfig{
+			Enabled:    true,
+			MaxEntries: 1000,
+			TTL:        10 * time.Minute,
+		},
+		Log: LogConfig{
+			Requests: true,
+			Output:   "stderr",
+		},
+	}
+}
+
+// Validate reports the first setting that cannot be used to start the
+// service, named by its file key.
+func (c *Config) Validate() error {
+	switch {
+	case !validAddr(c.Server.Addr):
+		return fmt.Errorf("invalid server.addr parameter")
+	case !validOrigins(c.Server.CORSOrigins):
+		return fmt.Errorf("invalid server.cors_origins parameter")
+	case c.Server.ShutdownGracePeriod < 0:
+		return fmt.Errorf("invalid server.shutdown_grace_period parameter")
+	case c.Server.MaxBatchIDs < 0:
+		return fmt.Errorf("invalid server.max_batch_ids parameter")
+	case c.Upstream.BaseURL != "" && !validHTTPURL(c.Upstream.BaseURL):
+		return fmt.Errorf("invalid upstream.base_url parameter")
+	case c.Upstream.Timeout < 0:
+		return fmt.Errorf("invalid upstream.timeout parameter")
+	case c.Upstream.MaxAttempts < 0:
+		return fmt.Errorf("invalid upstream.max_attempts parameter")
+	case c.Upstream.RetryJitter < 0 || c.Upstream.RetryJitter > 1:
+		return fmt.Errorf("invalid upstream.retry_jitter parameter")
+	case c.Upstream.RequestsPerSecond < 0:
+		return fmt.Errorf("invalid upstream.requests_per_second parameter")
+	case c.Upstream.Burst < 0:
+		return fmt.Errorf("invalid upstream.burst parameter")
+	case c.Upstream.FailureThreshold < 0:
+		return fmt.Errorf("invalid upstream.failure_threshold parameter")
+	case c.Cache.MaxEntries < 0:
+		return fmt.Errorf("invalid cache.max_entries parameter")
+	case c.Cache.TTL < 0:
+		return fmt.Errorf("invalid cache.ttl parameter")
+	case c.Log.Output != "stderr" && c.Log.Output != "stdout":
+		return fmt.Errorf("invalid log.output parameter")
+	}
+
+	return nil
+}
+
+// validAddr requires an explicit port so the service never listens on a
+// port picked at random.
+func validAddr(addr string) bool {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+
+	n, err := strconv.Atoi(port)
+
+	return err == nil && n > 0 && n <= 65535
+}
+
+func validOrigins(origins []string) bool {
+	for _, origin := range origins {
+		if origin != "*" && !validHTTPURL(origin) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func validHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// LogOutput returns the writer selected by Log.Output.
+func (c *Config) LogOutput() io.Writer {
+	if c.Log.Output == "stdout" {
+		return os.Stdout
+	}
+
+	return os.Stderr
+}
+
+func (c *Config) GatewayConfig() *rmGateway.GatewayConfig {
+	return &rmGateway.GatewayConfig{
+		BaseURL: c.Upstream.BaseURL,
+		Timeout: c.Upstream.Timeout,
+		Retry: rmGateway.RetryPolicy{
+			MaxAttempts: c.Upstream.MaxAttempts,
+			Jitter:      c.Upstream.RetryJitter,
+		},
+		CircuitBreaker: rmGateway.CircuitBreakerConfig{
+			FailureThreshold: c.Upstream.FailureThreshold,
+		},
+		RateLimit: rmGateway.RateLimitConfig{
+			RequestsPerSecond: c.Upstream.RequestsPerSecond,
+			Burst:             c.Upstream.Burst,
+		},
+	}
+}
+
+// CacheConfig returns nil when caching is disabled, as ApiRouterConfig
+// expects.
+func (c *Config) CacheConfig() *rmGateway.CacheConfig {
+	if !c.Cache.Enabled {
+		return nil
+	}
+
+	return &rmGateway.CacheConfig{
+		MaxEntries: c.Cache.MaxEntries,
+		DefaultTTL: c.Cache.TTL,
+	}
+}
+
+func (c *Config) ApiRouterConfig(handler chi.Router) *router.ApiRouterConfig {
+	return &router.ApiRouterConfig{
+		Handler:             handler,
+		Addr:                c.Server.Addr,
+		AllowedOrigins:      c.Server.CORSOrigins,
+		Gateway:             c.GatewayConfig(),
+		Cache:               c.CacheConfig(),
+		CoalesceRequests:    c.Upstream.CoalesceRequests,
+		ProblemDetails:      c.Server.ProblemDetails,
+		MaxBatchIDs:         c.Server.MaxBatchIDs,
+		CacheControl:        c.Server.CacheControl,
+		QuietRequests:       !c.Log.Requests,
+		LogOutput:           c.LogOutput(),
+		ShutdownGracePeriod: c.Server.ShutdownGracePeriod,
+	}
+}
@@ -0,0 +1,260 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// env returns a lookupEnv backed by vars.
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.FailNow()
+	}
+
+	return path
+}
+
+func TestConfig_Load(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns the defaults when nothing is set", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := Load(nil, env(nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, Default(), cfg)
+	})
+
+	t.Run("it reads a YAML file", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeFile(t, "gojo.yaml", `
+server:
+  addr: 127.0.0.1:9000
+  cors_origins:
+    - https://example.com
+    - https://example.org
+upstream:
+  timeout: 3s
+  retry_jitter: 0.5
+cache:
+  enabled: false
+`)
+
+		cfg, err := Load([]string{"-config", path}, env(nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "127.0.0.1:9000", cfg.Server.Addr)
+		assert.Equal(t, []string{"https://example.com", "https://example.org"}, cfg.Server.CORSOrigins)
+		assert.Equal(t, 3*time.Second, cfg.Upstream.Timeout)
+		assert.Equal(t, 0.5, cfg.Upstream.RetryJitter)
+		assert.False(t, cfg.Cache.Enabled)
+		assert.Equal(t, 1000, cfg.Cache.MaxEntries)
+	})
+
+	t.Run("it reads a JSON file named by GOJO_CONFIG", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeFile(t, "gojo.json", `{"cache": {"max_entries": 2000000}, "log": {"output": "stdout"}}`)
+
+		cfg, err := Load(nil, env(map[string]string{"GOJO_CONFIG": path}))
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2000000, cfg.Cache.MaxEntries)
+		assert.Equal(t, "stdout", cfg.Log.Output)
+	})
+
+	t.Run("it lets the environment override the file and flags override both", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeFile(t, "gojo.yml", "upstream:\n  max_attempts: 5\n  burst: 20\n  base_url: http://file.test/api\n")
+
+		cfg, err := Load(
+			[]string{"-config", path, "-upstream-burst", "30", "-log-requests=false"},
+			env(map[string]string{
+				"GOJO_UPSTREAM_BURST":    "25",
+				"GOJO_UPSTREAM_BASE_URL": "http://env.test/api",
+			}),
+		)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 5, cfg.Upstream.MaxAttempts)
+		assert.Equal(t, "http://env.test/api", cfg.Upstream.BaseURL)
+		assert.Equal(t, 30, cfg.Upstream.Burst)
+		assert.False(t, cfg.Log.Requests)
+	})
+
+	t.Run("it falls back to PORT when GOJO_SERVER_ADDR is unset", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := Load(nil, env(map[string]string{"PORT": "3000"}))
+
+		assert.Nil(t, err)
+		assert.Equal(t, ":3000", cfg.Server.Addr)
+
+		cfg, err = Load(nil, env(map[string]string{"PORT": "3000", "GOJO_SERVER_ADDR": ":4000"}))
+
+		assert.Nil(t, err)
+		assert.Equal(t, ":4000", cfg.Server.Addr)
+	})
+
+	t.Run("it splits comma-separated lists", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := Load(nil, env(map[string]string{"GOJO_SERVER_CORS_ORIGINS": "https://a.test, https://b.test,"}))
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"https://a.test", "https://b.test"}, cfg.Server.CORSOrigins)
+	})
+
+	t.Run("it returns an error naming the source of an unparsable value", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Load(nil, env(map[string]string{"GOJO_CACHE_TTL": "ten minutes"}))
+		assert.EqualError(t, fmt.Errorf("invalid cache.ttl parameter from GOJO_CACHE_TTL"), err.Error())
+
+		_, err = Load([]string{"-cache-enabled=maybe"}, env(nil))
+		assert.EqualError(t, fmt.Errorf("invalid cache.enabled parameter from -cache-enabled"), err.Error())
+	})
+
+	t.Run("it returns an error for unknown file settings", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeFile(t, "gojo.yaml", "upstream:\n  timout: 3s\n")
+
+		_, err := Load([]string{"-config", path}, env(nil))
+
+		assert.EqualError(t, fmt.Errorf("unknown setting %q in %s", "upstream.timout", path), err.Error())
+	})
+
+	t.Run("it returns an error for unsupported file extensions", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeFile(t, "gojo.toml", "")
+
+		_, err := Load([]string{"-config", path}, env(nil))
+
+		assert.EqualError(t, fmt.Errorf("unsupported config file extension %q", ".toml"), err.Error())
+	})
+
+	t.Run("it returns an error for positional arguments", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Load([]string{"serve"}, env(nil))
+
+		assert.EqualError(t, fmt.Errorf("unexpected argument %q", "serve"), err.Error())
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		modify   func(cfg *Config)
+		expected string
+	}{
+		"it rejects an address without a port": {
+			modify:   func(cfg *Config) { cfg.Server.Addr = ":" },
+			expected: "invalid server.addr parameter",
+		},
+		"it rejects a negative batch cap": {
+			modify:   func(cfg *Config) { cfg.Server.MaxBatchIDs = -1 },
+			expected: "invalid server.max_batch_ids parameter",
+		},
+		"it rejects origins that are not URLs": {
+			modify:   func(cfg *Config) { cfg.Server.CORSOrigins = []string{"localhost:8080"} },
+			expected: "invalid server.cors_origins parameter",
+		},
+		"it rejects a base URL without a scheme": {
+			modify:   func(cfg *Config) { cfg.Upstream.BaseURL = "rickandmortyapi.com/api" },
+			expected: "invalid upstream.base_url parameter",
+		},
+		"it rejects a negative timeout": {
+			modify:   func(cfg *Config) { cfg.Upstream.Timeout = -time.Second },
+			expected: "invalid upstream.timeout parameter",
+		},
+		"it rejects a jitter above 1": {
+			modify:   func(cfg *Config) { cfg.Upstream.RetryJitter = 1.5 },
+			expected: "invalid upstream.retry_jitter parameter",
+		},
+		"it rejects a negative cache size": {
+			modify:   func(cfg *Config) { cfg.Cache.MaxEntries = -1 },
+			expected: "invalid cache.max_entries parameter",
+		},
+		"it rejects an unknown log output": {
+			modify:   func(cfg *Config) { cfg.Log.Output = "syslog" },
+			expected: "invalid log.output parameter",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := Default()
+			test.modify(cfg)
+
+			assert.EqualError(t, cfg.Validate(), test.expected)
+		})
+	}
+
+	t.Run("it accepts a wildcard origin", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Default()
+		cfg.Server.CORSOrigins = []string{"*"}
+
+		assert.Nil(t, cfg.Validate())
+	})
+}
+
+func TestConfig_ApiRouterConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it maps settings onto the router and gateway configs", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Default()
+		cfg.Upstream.BaseURL = "http://upstream.test/api"
+		cfg.Log.Requests = false
+		cfg.Server.CacheControl = "private, max-age=5"
+
+		routerConfig := cfg.ApiRouterConfig(chi.NewRouter())
+
+		assert.Equal(t, ":8080", routerConfig.Addr)
+		assert.Equal(t, 200, routerConfig.MaxBatchIDs)
+		assert.Equal(t, "private, max-age=5", routerConfig.CacheControl)
+		assert.Equal(t, "http://upstream.test/api", routerConfig.Gateway.BaseURL)
+		assert.Equal(t, 3, routerConfig.Gateway.Retry.MaxAttempts)
+		assert.Equal(t, 10*time.Minute, routerConfig.Cache.DefaultTTL)
+		assert.True(t, routerConfig.CoalesceRequests)
+		assert.True(t, routerConfig.QuietRequests)
+		assert.Equal(t, os.Stderr, routerConfig.LogOutput)
+	})
+
+	t.Run("it leaves the cache out when disabled", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Default()
+		cfg.Cache.Enabled = false
+
+		assert.Nil(t, cfg.ApiRouterConfig(chi.NewRouter()).Cache)
+	})
+}
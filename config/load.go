@@ -0,0 +1,351 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	envPrefix     = "GOJO_"
+	configFileEnv = "GOJO_CONFIG"
+)
+
+// setting ties one Config field to its file key, from which the environment
+// variable and flag names are derived: upstream.base_url is read from
+// GOJO_UPSTREAM_BASE_URL and -upstream-base-url.
+type setting struct {
+	key   string
+	usage string
+	value flag.Value
+}
+
+func (s setting) envName() string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(s.key, ".", "_"))
+}
+
+func (s setting) flagName() string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(s.key)
+}
+
+// set parses raw into the setting, naming source in the error.
+func (s setting) set(raw, source string) error {
+	if err := s.value.Set(raw); err != nil {
+		return fmt.Errorf("invalid %s parameter from %s", s.key, source)
+	}
+
+	return nil
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{"server.addr", "address to listen on, such as :8080", stringValue{&c.Server.Addr}},
+		{"server.cors_origins", "comma-separated origins allowed by CORS; * allows any origin, without credentials", listValue{&c.Server.CORSOrigins}},
+		{"server.shutdown_grace_period", "how long in-flight requests may finish on shutdown", durationValue{&c.Server.ShutdownGracePeriod}},
+		{"server.problem_details", "render every error as application/problem+json", boolValue{&c.Server.ProblemDetails}},
+		{"server.max_batch_ids", "most distinct ids one multi-get may ask for", intValue{&c.Server.MaxBatchIDs}},
+		{"server.cache_control", "Cache-Control sent with cacheable character responses", stringValue{&c.Server.CacheControl}},
+		{"upstream.base_url", "base URL of the Rick and Morty API", stringValue{&c.Upstream.BaseURL}},
+		{"upstream.timeout", "timeout for each upstream request", durationValue{&c.Upstream.Timeout}},
+		{"upstream.max_attempts", "upstream attempts per request, including the first", intValue{&c.Upstream.MaxAttempts}},
+		{"upstream.retry_jitter", "fraction by which retry delays are randomised", floatValue{&c.Upstream.RetryJitter}},
+		{"upstream.requests_per_second", "upstream rate limit; 0 disables it", floatValue{&c.Upstream.RequestsPerSecond}},
+		{"upstream.burst", "upstream requests allowed in a burst", intValue{&c.Upstream.Burst}},
+		{"upstream.failure_threshold", "consecutive failures that open the circuit breaker; 0 disables it", intValue{&c.Upstream.FailureThreshold}},
+		{"upstream.coalesce_requests", "share one upstream call between identical concurrent requests", boolValue{&c.Upstream.CoalesceRequests}},
+		{"cache.enabled", "cache upstream responses", boolValue{&c.Cache.Enabled}},
+		{"cache.max_entries", "most cached responses kept", intValue{&c.Cache.MaxEntries}},
+		{"cache.ttl", "how long cached responses are served", durationValue{&c.Cache.TTL}},
+		{"log.requests", "write an access log line per request", boolValue{&c.Log.Requests}},
+		{"log.output", "where logs are written: stderr or stdout", stringValue{&c.Log.Output}},
+	}
+}
+
+// Load builds a Config from Default, then the file named by -config or
+// GOJO_CONFIG, then the environment, then args, and validates the result.
+// lookupEnv is usually os.LookupEnv. PORT is honoured as a fallback for
+// GOJO_SERVER_ADDR. A -h flag returns flag.ErrHelp after printing usage.
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
+	settings := cfg.settings()
+
+	// Flags take precedence over everything, but name the file to read first,
+	// so their values are held back until the file and environment are applied.
+	flags := flag.NewFlagSet("gojo", flag.ContinueOnError)
+	configFile := flags.String("config", "", "path to a YAML or JSON configuration file; also read from "+configFileEnv)
+
+	flagged := map[string]string{}
+	for _, s := range settings {
+		flags.Var(&deferredFlag{setting: s, flagged: flagged}, s.flagName(), s.usage)
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if flags.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	path := *configFile
+	if path == "" {
+		path, _ = lookupEnv(configFileEnv)
+	}
+
+	if path != "" {
+		if err := loadFile(path, settings); err != nil {
+			return nil, err
+		}
+	}
+
+	if port, ok := lookupEnv("PORT"); ok && port != "" {
+		cfg.Server.Addr = ":" + port
+	}
+
+	for _, s := range settings {
+		if raw, ok := lookupEnv(s.envName()); ok {
+			if err := s.set(raw, s.envName()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, s := range settings {
+		if raw, ok := flagged[s.key]; ok {
+			if err := s.set(raw, "-"+s.flagName()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// loadFile applies a YAML or JSON file, chosen by extension, whose nested
+// objects mirror the setting keys. Unknown keys are rejected so a typo does
+// not silently leave a default in place.
+func loadFile(path string, settings []setting) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	document := map[string]interface{}{}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&document)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &document)
+	default:
+		return fmt.Errorf("unsupported config file extension %q", filepath.Ext(path))
+	}
+
+	if err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+
+	values := map[string]string{}
+	flatten("", document, values)
+
+	byKey := make(map[string]setting, len(settings))
+	for _, s := range settings {
+		byKey[s.key] = s
+	}
+
+	for key := range values {
+		if _, ok := byKey[key]; !ok {
+			return fmt.Errorf("unknown setting %q in %s", key, path)
+		}
+	}
+
+	for _, s := range settings {
+		if raw, ok := values[s.key]; ok {
+			if err := s.set(raw, path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// flatten turns nested objects into dotted keys and scalars into the same
+// strings the environment would carry; lists become comma-separated.
+func flatten(prefix string, node map[string]interface{}, values map[string]string) {
+	for name, value := range node {
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flatten(key, v, values)
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			values[key] = strings.Join(items, ",")
+		case nil:
+		default:
+			values[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// deferredFlag records a flag's raw value so Load can apply it last.
+type deferredFlag struct {
+	setting setting
+	flagged map[string]string
+}
+
+func (f *deferredFlag) Set(raw string) error {
+	f.flagged[f.setting.key] = raw
+	return nil
+}
+
+func (f *deferredFlag) String() string {
+	if f == nil || f.setting.value == nil {
+		return ""
+	}
+
+	return f.setting.value.String()
+}
+
+func (f *deferredFlag) IsBoolFlag() bool {
+	_, ok := f.setting.value.(boolValue)
+	return ok
+}
+
+type stringValue struct{ p *string }
+
+func (v stringValue) Set(raw string) error {
+	*v.p = raw
+	return nil
+}
+
+func (v stringValue) String() string {
+	if v.p == nil {
+		return ""
+	}
+
+	return *v.p
+}
+
+type listValue struct{ p *[]string }
+
+func (v listValue) Set(raw string) error {
+	items := []string{}
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	*v.p = items
+	return nil
+}
+
+func (v listValue) String() string {
+	if v.p == nil {
+		return ""
+	}
+
+	return strings.Join(*v.p, ",")
+}
+
+type durationValue struct{ p *time.Duration }
+
+func (v durationValue) Set(raw string) error {
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return err
+	}
+
+	*v.p = d
+	return nil
+}
+
+func (v durationValue) String() string {
+	if v.p == nil {
+		return ""
+	}
+
+	return v.p.String()
+}
+
+type intValue struct{ p *int }
+
+func (v intValue) Set(raw string) error {
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return err
+	}
+
+	*v.p = n
+	return nil
+}
+
+func (v intValue) String() string {
+	if v.p == nil {
+		return ""
+	}
+
+	return strconv.Itoa(*v.p)
+}
+
+type floatValue struct{ p *float64 }
+
+func (v floatValue) Set(raw string) error {
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return err
+	}
+
+	*v.p = f
+	return nil
+}
+
+func (v floatValue) String() string {
+	if v.p == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(*v.p, 'g', -1, 64)
+}
+
+type boolValue struct{ p *bool }
+
+func (v boolValue) Set(raw string) error {
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return err
+	}
+
+	*v.p = b
+	return nil
+}
+
+func (v boolValue) String() string {
+	if v.p == nil {
+		return ""
+	}
+
+	return strconv.FormatBool(*v.p)
+}
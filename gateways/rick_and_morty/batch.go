@@ -0,0 +1,113 @@
+package rick_and_morty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// getMany fetches ids from a resource's multi-get endpoint, splitting them
+// into requests of at most g.maxBatchSize ids that run in parallel like
+// listing pages. Items come back once each, in the order their ids were
+// requested; ids the upstream did not return are reported as missing.
+func getMany[T any](ctx context.Context, g *gateway, resource string, ids []int, idOf func(T) int) ([]T, []int, error) {
+	ids = uniqueIDs(ids)
+	chunks := chunkIDs(ids, g.maxBatchSize)
+	results := make([][]T, len(chunks))
+
+	err := runParallel(ctx, len(chunks), g.maxConcurrentPages, func(ctx context.Context, i int) error {
+		items, err := getChunk[T](ctx, g, resource, chunks[i])
+		if err != nil {
+			return err
+		}
+
+		results[i] = items
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	found := make(map[int]T, len(ids))
+	for _, items := range results {
+		for _, item := range items {
+			found[idOf(item)] = item
+		}
+	}
+
+	var missing []int
+	items := make([]T, 0, len(ids))
+
+	for _, id := range ids {
+		item, ok := found[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	return items, missing, nil
+}
+
+// getChunk makes one multi-get request. Given a single id, the upstream
+// answers with a bare object rather than a one-element array, or with a 404
+// rather than an empty array when the id does not exist.
+func getChunk[T any](ctx context.Context, g *gateway, resource string, ids []int) ([]T, error) {
+	var raw json.RawMessage
+
+	err := g.getJSON(ctx, g.baseURI+resource+"/"+joinIDs(ids), &raw)
+	switch {
+	case len(ids) == 1 && errors.Is(err, ErrNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+		var item T
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return nil, decodeError(err)
+		}
+
+		return []T{item}, nil
+	}
+
+	var items []T
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, decodeError(err)
+	}
+
+	return items, nil
+}
+
+func uniqueIDs(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	return unique
+}
+
+func chunkIDs(ids []int, size int) [][]int {
+	var chunks [][]int
+
+	for len(ids) > size {
+		chunks = append(chunks, ids[:size])
+		ids = ids[size:]
+	}
+
+	if len(ids) > 0 {
+		chunks = append(chunks, ids)
+	}
+
+	return chunks
+}
@@ -0,0 +1,142 @@
+package rick_and_morty
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGateway_Batching(t *testing.T) {
+	t.Parallel()
+
+	// charactersResponder answers a multi-get with the requested ids that are
+	// not in absent, as the upstream does for ids that do not exist.
+	charactersResponder := func(absent ...int) httpmock.Responder {
+		return func(req *http.Request) (*http.Response, error) {
+			idList := strings.TrimPrefix(req.URL.Path, "/api/character/")
+
+			characters := []Character{}
+			for _, part := range strings.Split(idList, ",") {
+				id, _ := strconv.Atoi(part)
+
+				skip := false
+				for _, a := range absent {
+					skip = skip || a == id
+				}
+
+				if !skip {
+					characters = append(characters, Character{Id: id})
+				}
+			}
+
+			return httpmock.NewJsonResponse(200, characters)
+		}
+	}
+
+	t.Run("it returns an error when an invalid MaxBatchSize is passed in", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewGateway(&GatewayConfig{MaxBatchSize: -1})
+
+		assert.EqualError(t, fmt.Errorf("invalid MaxBatchSize parameter"), err.Error())
+	})
+
+	t.Run("it splits large id sets into several requests", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{
+			MaxBatchSize: 2,
+		})
+
+		for _, path := range []string{"5,4", "3,2", "1"} {
+			transport.RegisterResponder("GET", defaultBaseURI+"character/"+path, charactersResponder())
+		}
+
+		result, err := g.GetCharacters(context.Background(), []int{5, 4, 3, 2, 1})
+
+		assert.Equal(t, CharacterBatch{Characters: []Character{{Id: 5}, {Id: 4}, {Id: 3}, {Id: 2}, {Id: 1}}}, result)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, transport.GetCallCountInfo()["GET "+defaultBaseURI+"character/5,4"])
+		assert.Equal(t, 1, transport.GetCallCountInfo()["GET "+defaultBaseURI+"character/3,2"])
+		assert.Equal(t, 1, transport.GetCallCountInfo()["GET "+defaultBaseURI+"character/1"])
+	})
+
+	t.Run("it requests each id once and reports the ones not returned", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/9,1,7,2", charactersResponder(7, 9))
+
+		result, err := g.GetCharacters(context.Background(), []int{9, 1, 7, 1, 2})
+
+		assert.Equal(t, CharacterBatch{Characters: []Character{{Id: 1}, {Id: 2}}, Missing: []int{9, 7}}, result)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, transport.GetTotalCallCount())
+	})
+
+	t.Run("it accepts the bare object returned for a single id", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/1",
+			httpmock.NewJsonResponderOrPanic(200, Character{Id: 1, Name: "Rick Sanchez"}))
+
+		result, err := g.GetCharacters(context.Background(), []int{1})
+
+		assert.Equal(t, CharacterBatch{Characters: []Character{{Id: 1, Name: "Rick Sanchez"}}}, result)
+		assert.Nil(t, err)
+	})
+
+	t.Run("it reports a single id the upstream cannot find as missing", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{
+			MaxBatchSize: 2,
+		})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/1,2", charactersResponder())
+		transport.RegisterResponder("GET", defaultBaseURI+"character/999",
+			httpmock.NewStringResponder(404, `{"error":"Character not found"}`))
+
+		result, err := g.GetCharacters(context.Background(), []int{1, 2, 999})
+
+		assert.Equal(t, CharacterBatch{Characters: []Character{{Id: 1}, {Id: 2}}, Missing: []int{999}}, result)
+		assert.Nil(t, err)
+	})
+
+	t.Run("it fails the whole batch when one request fails", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{
+			MaxBatchSize: 2,
+		})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/1,2", charactersResponder())
+		transport.RegisterResponder("GET", defaultBaseURI+"character/3,4",
+			httpmock.NewStringResponder(400, `{"error":"bad request"}`))
+
+		result, err := g.GetCharacters(context.Background(), []int{1, 2, 3, 4})
+
+		assert.Equal(t, CharacterBatch{Characters: []Character{}}, result)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+}
@@ -0,0 +1,340 @@
+package rick_and_morty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultCacheMaxEntries = 1000
+	defaultCacheTTL        = 10 * time.Minute
+)
+
+const (
+	characterKeyPrefix      = "character:"
+	charactersKeyPrefix     = "characters:"
+	searchKeyPrefix         = "search:"
+	listKey                 = "list"
+	episodeKeyPrefix        = "episode:"
+	episodesKeyPrefix       = "episodes:"
+	episodeSearchKeyPrefix  = "episode-search:"
+	episodeListKey          = "episode-list"
+	locationKeyPrefix       = "location:"
+	locationsKeyPrefix      = "locations:"
+	locationSearchKeyPrefix = "location-search:"
+	locationListKey         = "location-list"
+)
+
+// CachedGateway is a Gateway that serves repeated calls from memory.
+type CachedGateway interface {
+	Gateway
+	Stats() CacheStats
+	InvalidateCharacter(id int)
+	Purge()
+}
+
+type CacheStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Entries   int    `json:"entries"`
+}
+
+// CacheConfig configures NewCachedGateway. The per-method TTLs apply to every
+// resource: GetTTL to single gets, GetManyTTL to multi-gets, and so on. Each
+// falls back to DefaultTTL when zero; a negative TTL disables caching for
+// that method.
+type CacheConfig struct {
+	Gateway    Gateway
+	MaxEntries int // Least recently used entries are evicted beyond this; defaults to 1000.
+
+	DefaultTTL time.Duration // Defaults to 10 minutes.
+	GetTTL     time.Duration
+	GetManyTTL time.Duration
+	SearchTTL  time.Duration
+	ListTTL    time.Duration
+}
+
+type cachedGateway struct {
+	gateway Gateway
+	entries *lruCache
+
+	getTTL     time.Duration
+	getManyTTL time.Duration
+	searchTTL  time.Duration
+	listTTL    time.Duration
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+func NewCachedGateway(cfg *CacheConfig) (CachedGateway, error) {
+	switch {
+	case cfg == nil:
+		return nil, fmt.Errorf("missing config parameter")
+	case cfg.Gateway == nil:
+		return nil, fmt.Errorf("missing Gateway parameter")
+	case cfg.MaxEntries < 0:
+		return nil, fmt.Errorf("invalid MaxEntries parameter")
+	case cfg.DefaultTTL < 0:
+		return nil, fmt.Errorf("invalid DefaultTTL parameter")
+	}
+
+	maxEntries := cfg.MaxEntries
+	if maxEntries == 0 {
+		maxEntries = defaultCacheMaxEntries
+	}
+
+	defaultTTL := cfg.DefaultTTL
+	if defaultTTL == 0 {
+		defaultTTL = defaultCacheTTL
+	}
+
+	ttlOrDefault := func(ttl time.Duration) time.Duration {
+		if ttl == 0 {
+			return defaultTTL
+		}
+		return ttl
+	}
+
+	c := &cachedGateway{
+		gateway:    cfg.Gateway,
+		getTTL:     ttlOrDefault(cfg.GetTTL),
+		getManyTTL: ttlOrDefault(cfg.GetManyTTL),
+		searchTTL:  ttlOrDefault(cfg.SearchTTL),
+		listTTL:    ttlOrDefault(cfg.ListTTL),
+	}
+
+	c.entries = newLRUCache(maxEntries, time.Now, func() {
+		atomic.AddUint64(&c.evictions, 1)
+	})
+
+	return c, nil
+}
+
+func (c *cachedGateway) GetCharacter(ctx context.Context, id int) (Character, error) {
+	return cached(ctx, c, characterKeyPrefix+strconv.Itoa(id), c.getTTL, func(ctx context.Context) (Character, error) {
+		return c.gateway.GetCharacter(ctx, id)
+	})
+}
+
+func (c *cachedGateway) GetCharacters(ctx context.Context, ids []int) (CharacterBatch, error) {
+	batch, err := cached(ctx, c, charactersKeyPrefix+joinIDs(ids), c.getManyTTL, func(ctx context.Context) (CharacterBatch, error) {
+		return c.gateway.GetCharacters(ctx, ids)
+	})
+
+	return batch.copy(), err
+}
+
+func (c *cachedGateway) SearchCharacters(ctx context.Context, filter CharacterFilter) ([]Character, error) {
+	characterList, err := cached(ctx, c, searchKeyPrefix+filter.query().Encode(), c.searchTTL, func(ctx context.Context) ([]Character, error) {
+		return c.gateway.SearchCharacters(ctx, filter)
+	})
+
+	return copySlice(characterList), err
+}
+
+func (c *cachedGateway) ListCharacters(ctx context.Context) ([]Character, error) {
+	characterList, err := cached(ctx, c, listKey, c.listTTL, func(ctx context.Context) ([]Character, error) {
+		return c.gateway.ListCharacters(ctx)
+	})
+
+	return copySlice(characterList), err
+}
+
+func (c *cachedGateway) GetEpisode(ctx context.Context, id int) (Episode, error) {
+	return cached(ctx, c, episodeKeyPrefix+strconv.Itoa(id), c.getTTL, func(ctx context.Context) (Episode, error) {
+		return c.gateway.GetEpisode(ctx, id)
+	})
+}
+
+func (c *cachedGateway) GetEpisodes(ctx context.Context, ids []int) ([]Episode, error) {
+	episodeList, err := cached(ctx, c, episodesKeyPrefix+joinIDs(ids), c.getManyTTL, func(ctx context.Context) ([]Episode, error) {
+		return c.gateway.GetEpisodes(ctx, ids)
+	})
+
+	return copySlice(episodeList), err
+}
+
+func (c *cachedGateway) SearchEpisodes(ctx context.Context, filter EpisodeFilter) ([]Episode, error) {
+	episodeList, err := cached(ctx, c, episodeSearchKeyPrefix+filter.query().Encode(), c.searchTTL, func(ctx context.Context) ([]Episode, error) {
+		return c.gateway.SearchEpisodes(ctx, filter)
+	})
+
+	return copySlice(episodeList), err
+}
+
+func (c *cachedGateway) ListEpisodes(ctx context.Context) ([]Episode, error) {
+	episodeList, err := cached(ctx, c, episodeListKey, c.listTTL, func(ctx context.Context) ([]Episode, error) {
+		return c.gateway.ListEpisodes(ctx)
+	})
+
+	return copySlice(episodeList), err
+}
+
+func (c *cachedGateway) GetLocation(ctx context.Context, id int) (Location, error) {
+	return cached(ctx, c, locationKeyPrefix+strconv.Itoa(id), c.getTTL, func(ctx context.Context) (Location, error) {
+		return c.gateway.GetLocation(ctx, id)
+	})
+}
+
+func (c *cachedGateway) GetLocations(ctx context.Context, ids []int) ([]Location, error) {
+	locationList, err := cached(ctx, c, locationsKeyPrefix+joinIDs(ids), c.getManyTTL, func(ctx context.Context) ([]Location, error) {
+		return c.gateway.GetLocations(ctx, ids)
+	})
+
+	return copySlice(locationList), err
+}
+
+func (c *cachedGateway) SearchLocations(ctx context.Context, filter LocationFilter) ([]Location, error) {
+	locationList, err := cached(ctx, c, locationSearchKeyPrefix+filter.query().Encode(), c.searchTTL, func(ctx context.Context) ([]Location, error) {
+		return c.gateway.SearchLocations(ctx, filter)
+	})
+
+	return copySlice(locationList), err
+}
+
+func (c *cachedGateway) ListLocations(ctx context.Context) ([]Location, error) {
+	locationList, err := cached(ctx, c, locationListKey, c.listTTL, func(ctx context.Context) ([]Location, error) {
+		return c.gateway.ListLocations(ctx)
+	})
+
+	return copySlice(locationList), err
+}
+
+// SearchCharacterPages streams straight from the wrapped Gateway; paging
+// through a listing is already incremental, so it is not cached.
+func (c *cachedGateway) SearchCharacterPages(ctx context.Context, filter CharacterFilter) CharacterIterator {
+	return c.gateway.SearchCharacterPages(ctx, filter)
+}
+
+func (c *cachedGateway) ListCharacterPages(ctx context.Context) CharacterIterator {
+	return c.gateway.ListCharacterPages(ctx)
+}
+
+func (c *cachedGateway) CircuitState() CircuitState {
+	return c.gateway.CircuitState()
+}
+
+// Close drops every cached entry and closes the wrapped Gateway.
+func (c *cachedGateway) Close() error {
+	c.entries.purge()
+
+	return c.gateway.Close()
+}
+
+func (c *cachedGateway) Stats() CacheStats {
+	return CacheStats{
+		Hits:      atomic.LoadUint64(&c.hits),
+		Misses:    atomic.LoadUint64(&c.misses),
+		Evictions: atomic.LoadUint64(&c.evictions),
+		Entries:   c.entries.len(),
+	}
+}
+
+// InvalidateCharacter drops the cached character with the given id, every
+// cached multi-get that includes it, and all search and list results, since
+// any of them may embed the stale character.
+func (c *cachedGateway) InvalidateCharacter(id int) {
+	idText := strconv.Itoa(id)
+
+	c.entries.removeMatching(func(key string) bool {
+		switch {
+		case key == characterKeyPrefix+idText, key == listKey, strings.HasPrefix(key, searchKeyPrefix):
+			return true
+		case strings.HasPrefix(key, charactersKeyPrefix):
+			for _, cachedID := range strings.Split(strings.TrimPrefix(key, charactersKeyPrefix), ",") {
+				if cachedID == idText {
+					return true
+				}
+			}
+		}
+		return false
+	})
+}
+
+func (c *cachedGateway) Purge() {
+	c.entries.purge()
+}
+
+// cacheEntry is a cached result together with the validators of the
+// upstream response it came from, if there was exactly one.
+type cacheEntry struct {
+	value      interface{}
+	validators validators
+}
+
+// cached returns the live entry for key, or calls fetch and stores its
+// result for ttl. Errors are never cached. An expired entry with validators
+// is revalidated: fetch's upstream request is made conditional, and a 304
+// keeps the entry for another ttl instead of replacing it.
+func cached[T any](ctx context.Context, c *cachedGateway, key string, ttl time.Duration, fetch func(ctx context.Context) (T, error)) (T, error) {
+	if ttl < 0 {
+		return fetch(ctx)
+	}
+
+	stored, fresh, ok := c.entries.get(key)
+	if ok && fresh {
+		atomic.AddUint64(&c.hits, 1)
+		return stored.(cacheEntry).value.(T), nil
+	}
+
+	atomic.AddUint64(&c.misses, 1)
+
+	var stale cacheEntry
+	if ok {
+		stale = stored.(cacheEntry)
+	}
+
+	revalidatingCtx, revalidation := withRevalidation(ctx, stale.validators)
+
+	value, err := fetch(revalidatingCtx)
+	if errors.Is(err, errNotModified) {
+		if ok {
+			received, revalidated := revalidation.result()
+			if !revalidated {
+				received = stale.validators
+			}
+
+			c.entries.set(key, cacheEntry{value: stale.value, validators: received}, ttl)
+
+			return stale.value.(T), nil
+		}
+
+		// A coalesced call revalidated an entry this call no longer has.
+		value, err = fetch(ctx)
+	}
+	if err != nil {
+		return value, err
+	}
+
+	received, _ := revalidation.result()
+	c.entries.set(key, cacheEntry{value: value, validators: received}, ttl)
+
+	return value, nil
+}
+
+// copy returns a batch whose slices can be modified without touching a
+// cached or shared copy.
+func (b CharacterBatch) copy() CharacterBatch {
+	return CharacterBatch{
+		Characters: copySlice(b.Characters),
+		Missing:    copySlice(b.Missing),
+	}
+}
+
+// copySlice keeps callers that reorder or modify a returned slice from
+// corrupting the shared copy.
+func copySlice[T any](values []T) []T {
+	if values == nil {
+		return nil
+	}
+
+	return append(make([]T, 0, len(values)), values...)
+}
@@ -0,0 +1,301 @@
+package rick_and_morty
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newCachedTestGateway(t *testing.T, cfg CacheConfig) (*cachedGateway, *httpmock.MockTransport, *fakeClock) {
+	g, transport := newTestGateway(t, GatewayConfig{})
+
+	cfg.Gateway = g
+
+	c, err := NewCachedGateway(&cfg)
+	if err != nil {
+		t.FailNow()
+	}
+
+	clock := &fakeClock{now: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	cachedGateway := c.(*cachedGateway)
+	cachedGateway.entries.now = clock.Now
+
+	return cachedGateway, transport, clock
+}
+
+func TestCachedGateway_NewCachedGateway(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns an error when no config passed in", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewCachedGateway(nil)
+
+		assert.EqualError(t, fmt.Errorf("missing config parameter"), err.Error())
+	})
+
+	t.Run("it returns an error when no Gateway passed in", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewCachedGateway(&CacheConfig{})
+
+		assert.EqualError(t, fmt.Errorf("missing Gateway parameter"), err.Error())
+	})
+
+	t.Run("it returns an error when an invalid MaxEntries is passed in", func(t *testing.T) {
+		t.Parallel()
+
+		g, _ := NewGateway(&GatewayConfig{})
+
+		_, err := NewCachedGateway(&CacheConfig{Gateway: g, MaxEntries: -1})
+
+		assert.EqualError(t, fmt.Errorf("invalid MaxEntries parameter"), err.Error())
+	})
+
+	t.Run("it successfully returns a CachedGateway", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, _ := NewGateway(&GatewayConfig{})
+
+		_, err := NewCachedGateway(&CacheConfig{Gateway: g})
+
+		assert.Nil(t, err)
+	})
+}
+
+func TestCachedGateway_GetCharacter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it serves repeated calls from the cache until the TTL expires", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c, transport, clock := newCachedTestGateway(t, CacheConfig{GetTTL: time.Minute})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			httpmock.NewStringResponder(200, `{"id": 1, "name": "Rick Sanchez"}`))
+
+		for i := 0; i < 3; i++ {
+			result, err := c.GetCharacter(context.Background(), testCharacterID)
+
+			assert.Equal(t, Character{Id: 1, Name: "Rick Sanchez"}, result)
+			assert.Nil(t, err)
+		}
+
+		assert.Equal(t, 1, transport.GetTotalCallCount())
+		assert.Equal(t, CacheStats{Hits: 2, Misses: 1, Entries: 1}, c.Stats())
+
+		clock.Advance(time.Minute)
+
+		_, err := c.GetCharacter(context.Background(), testCharacterID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, transport.GetTotalCallCount())
+	})
+
+	t.Run("it does not cache errors", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c, transport, _ := newCachedTestGateway(t, CacheConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			httpmock.NewStringResponder(404, `{"error":"Character not found"}`))
+
+		for i := 0; i < 2; i++ {
+			_, err := c.GetCharacter(context.Background(), testCharacterID)
+
+			assert.ErrorIs(t, err, ErrNotFound)
+		}
+
+		assert.Equal(t, 2, transport.GetTotalCallCount())
+		assert.Equal(t, 0, c.Stats().Entries)
+	})
+
+	t.Run("it bypasses the cache when the TTL is negative", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c, transport, _ := newCachedTestGateway(t, CacheConfig{GetTTL: -1})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			httpmock.NewStringResponder(200, `{"id": 1}`))
+
+		for i := 0; i < 2; i++ {
+			_, err := c.GetCharacter(context.Background(), testCharacterID)
+
+			assert.Nil(t, err)
+		}
+
+		assert.Equal(t, 2, transport.GetTotalCallCount())
+		assert.Equal(t, CacheStats{}, c.Stats())
+	})
+
+	t.Run("it evicts the least recently used entry beyond MaxEntries", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c, transport, _ := newCachedTestGateway(t, CacheConfig{MaxEntries: 2})
+
+		for _, id := range []string{"1", "2", "3"} {
+			transport.RegisterResponder("GET", defaultBaseURI+"character/"+id,
+				httpmock.NewStringResponder(200, `{"id": `+id+`}`))
+		}
+
+		for _, id := range []int{1, 2, 1, 3, 1, 2} {
+			_, err := c.GetCharacter(context.Background(), id)
+
+			assert.Nil(t, err)
+		}
+
+		callCounts := transport.GetCallCountInfo()
+
+		assert.Equal(t, 1, callCounts["GET "+defaultBaseURI+"character/1"])
+		assert.Equal(t, 2, callCounts["GET "+defaultBaseURI+"character/2"])
+		assert.Equal(t, 1, callCounts["GET "+defaultBaseURI+"character/3"])
+		assert.Equal(t, CacheStats{Hits: 2, Misses: 4, Evictions: 2, Entries: 2}, c.Stats())
+	})
+}
+
+func TestCachedGateway_ListCharacters(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns a copy so callers cannot modify the cached list", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c, transport, _ := newCachedTestGateway(t, CacheConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, CharactersListResponse{
+					Info:    ApiInfo{Count: 2, Pages: 1},
+					Results: []Character{{Id: 1}, {Id: 2}},
+				})
+			})
+
+		first, err := c.ListCharacters(context.Background())
+		assert.Nil(t, err)
+
+		first[0], first[1] = first[1], first[0]
+
+		second, err := c.ListCharacters(context.Background())
+
+		assert.Nil(t, err)
+		assert.Equal(t, []Character{{Id: 1}, {Id: 2}}, second)
+		assert.Equal(t, 1, transport.GetTotalCallCount())
+	})
+}
+
+func TestCachedGateway_Invalidation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it drops every entry that may contain the invalidated character", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c, transport, _ := newCachedTestGateway(t, CacheConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/1",
+			httpmock.NewStringResponder(200, `{"id": 1}`))
+		transport.RegisterResponder("GET", defaultBaseURI+"character/2",
+			httpmock.NewStringResponder(200, `{"id": 2}`))
+		transport.RegisterResponder("GET", defaultBaseURI+"character/1,2",
+			httpmock.NewStringResponder(200, `[{"id": 1}, {"id": 2}]`))
+		transport.RegisterResponder("GET", defaultBaseURI+"character/2,3",
+			httpmock.NewStringResponder(200, `[{"id": 2}, {"id": 3}]`))
+		transport.RegisterResponder("GET", defaultBaseURI+"character?name="+testSearchCharacterQuery,
+			httpmock.NewStringResponder(200, `{"info": {"count": 1, "pages": 1}, "results": [{"id": 1}]}`))
+
+		ctx := context.Background()
+
+		_, _ = c.GetCharacter(ctx, 1)
+		_, _ = c.GetCharacter(ctx, 2)
+		_, _ = c.GetCharacters(ctx, []int{1, 2})
+		_, _ = c.GetCharacters(ctx, []int{2, 3})
+		_, _ = c.SearchCharacters(ctx, CharacterFilter{Name: testSearchCharacterQuery})
+
+		c.InvalidateCharacter(1)
+
+		assert.Equal(t, 2, c.Stats().Entries)
+
+		c.Purge()
+
+		assert.Equal(t, 0, c.Stats().Entries)
+	})
+
+	t.Run("it purges every entry on Close", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c, transport, _ := newCachedTestGateway(t, CacheConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/1",
+			httpmock.NewStringResponder(200, `{"id": 1}`))
+
+		_, _ = c.GetCharacter(context.Background(), 1)
+
+		assert.Nil(t, c.Close())
+		assert.Equal(t, 0, c.Stats().Entries)
+	})
+}
+
+func TestCachedGateway_SearchEpisodes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it caches each distinct filter separately", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c, transport, _ := newCachedTestGateway(t, CacheConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"episode",
+			httpmock.NewStringResponder(200, `{"info": {"count": 1, "pages": 1}, "results": [{"id": 1}]}`))
+
+		ctx := context.Background()
+
+		for i := 0; i < 2; i++ {
+			_, _ = c.SearchEpisodes(ctx, EpisodeFilter{Episode: "S01"})
+			_, _ = c.SearchEpisodes(ctx, EpisodeFilter{Episode: "S01", Name: "Pilot"})
+		}
+
+		assert.Equal(t, 2, transport.GetTotalCallCount())
+		assert.Equal(t, CacheStats{Hits: 2, Misses: 2, Entries: 2}, c.Stats())
+	})
+}
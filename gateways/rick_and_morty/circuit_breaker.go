@@ -0,0 +1,200 @@
+package rick_and_morty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCircuitCoolDown            = 30 * time.Second
+	defaultCircuitHalfOpenMaxRequests = 1
+)
+
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// CircuitBreakerConfig controls the breaker around upstream requests. After
+// FailureThreshold consecutive transport failures, timeouts or 5xx responses
+// the circuit opens and requests fail fast with ErrCircuitOpen. Once CoolDown
+// has passed, up to HalfOpenMaxRequests probe requests are let through: one
+// success closes the circuit, a failure opens it again. A zero
+// FailureThreshold disables the breaker.
+type CircuitBreakerConfig struct {
+	FailureThreshold    int
+	CoolDown            time.Duration // Defaults to 30 seconds.
+	HalfOpenMaxRequests int           // Defaults to 1.
+}
+
+type circuitBreaker struct {
+	failureThreshold    int
+	coolDown            time.Duration
+	halfOpenMaxRequests int
+	now                 func() time.Time
+
+	mu               sync.Mutex
+	state            CircuitState
+	failures         int
+	openedAt         time.Time
+	halfOpenInFlight int
+}
+
+// newCircuitBreaker returns nil when the breaker is disabled; a nil
+// *circuitBreaker lets every request through.
+func newCircuitBreaker(cfg CircuitBreakerConfig) (*circuitBreaker, error) {
+	switch {
+	case cfg.FailureThreshold < 0:
+		return nil, fmt.Errorf("invalid CircuitBreaker.FailureThreshold parameter")
+	case cfg.CoolDown < 0:
+		return nil, fmt.Errorf("invalid CircuitBreaker.CoolDown parameter")
+	case cfg.HalfOpenMaxRequests < 0:
+		return nil, fmt.Errorf("invalid CircuitBreaker.HalfOpenMaxRequests parameter")
+	case cfg.FailureThreshold == 0:
+		return nil, nil
+	}
+
+	coolDown := cfg.CoolDown
+	if coolDown == 0 {
+		coolDown = defaultCircuitCoolDown
+	}
+
+	halfOpenMaxRequests := cfg.HalfOpenMaxRequests
+	if halfOpenMaxRequests == 0 {
+		halfOpenMaxRequests = defaultCircuitHalfOpenMaxRequests
+	}
+
+	return &circuitBreaker{
+		failureThreshold:    cfg.FailureThreshold,
+		coolDown:            coolDown,
+		halfOpenMaxRequests: halfOpenMaxRequests,
+		now:                 time.Now,
+		state:               CircuitClosed,
+	}, nil
+}
+
+// call runs request unless the circuit is open, and records its outcome.
+// Failures caused by ctx itself ending are not held against the upstream.
+func (b *circuitBreaker) call(ctx context.Context, request func() error) error {
+	if b == nil {
+		return request()
+	}
+
+	if retryAfter, ok := b.allow(); !ok {
+		return &UpstreamError{
+			Kind:       ErrCircuitOpen,
+			RetryAfter: retryAfter,
+		}
+	}
+
+	err := request()
+
+	switch {
+	case ctx.Err() != nil:
+		b.release()
+	case errors.Is(err, ErrUpstreamUnavailable), errors.Is(err, ErrUpstreamTimeout):
+		b.recordFailure()
+	default:
+		b.recordSuccess()
+	}
+
+	return err
+}
+
+func (b *circuitBreaker) State() CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && b.coolDownElapsed() {
+		return CircuitHalfOpen
+	}
+
+	return b.state
+}
+
+// allow reports whether a request may proceed, and if not, how long until
+// the circuit will next let a request through.
+func (b *circuitBreaker) allow() (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen {
+		if !b.coolDownElapsed() {
+			return b.openedAt.Add(b.coolDown).Sub(b.now()), false
+		}
+
+		b.state = CircuitHalfOpen
+		b.halfOpenInFlight = 0
+	}
+
+	if b.state == CircuitHalfOpen {
+		if b.halfOpenInFlight >= b.halfOpenMaxRequests {
+			return b.coolDown, false
+		}
+		b.halfOpenInFlight++
+	}
+
+	return 0, true
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitClosed:
+		b.failures = 0
+	case CircuitHalfOpen:
+		b.state = CircuitClosed
+		b.failures = 0
+		b.halfOpenInFlight = 0
+	}
+}
+
+func (b *circuitBreaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitClosed:
+		b.failures++
+		if b.failures >= b.failureThreshold {
+			b.open()
+		}
+	case CircuitHalfOpen:
+		b.open()
+	}
+}
+
+// release frees a half-open probe slot without judging the upstream.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitHalfOpen && b.halfOpenInFlight > 0 {
+		b.halfOpenInFlight--
+	}
+}
+
+// open trips the circuit. b.mu must be held.
+func (b *circuitBreaker) open() {
+	b.state = CircuitOpen
+	b.openedAt = b.now()
+	b.halfOpenInFlight = 0
+}
+
+// coolDownElapsed reports whether an open circuit may be probed. b.mu must
+// be held.
+func (b *circuitBreaker) coolDownElapsed() bool {
+	return !b.now().Before(b.openedAt.Add(b.coolDown))
+}
@@ -0,0 +1,212 @@
+package rick_and_morty
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBreakingTestGateway(t *testing.T, cfg CircuitBreakerConfig) (*gateway, *httpmock.MockTransport, *fakeClock) {
+	g, transport := newTestGateway(t, GatewayConfig{CircuitBreaker: cfg})
+
+	clock := &fakeClock{now: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+	g.breaker.now = clock.Now
+
+	return g, transport, clock
+}
+
+func TestGateway_CircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns an error when an invalid config is passed in", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewGateway(&GatewayConfig{CircuitBreaker: CircuitBreakerConfig{FailureThreshold: -1}})
+
+		assert.EqualError(t, fmt.Errorf("invalid CircuitBreaker.FailureThreshold parameter"), err.Error())
+	})
+
+	t.Run("it always reports a closed circuit when disabled", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			httpmock.NewStringResponder(503, ""))
+
+		for i := 0; i < 10; i++ {
+			_, err := g.GetCharacter(context.Background(), testCharacterID)
+
+			assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+		}
+
+		assert.Equal(t, CircuitClosed, g.CircuitState())
+		assert.Equal(t, 10, transport.GetTotalCallCount())
+	})
+
+	t.Run("it opens after consecutive failures and fails fast", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport, _ := newBreakingTestGateway(t, CircuitBreakerConfig{
+			FailureThreshold: 3,
+			CoolDown:         time.Minute,
+		})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			httpmock.NewStringResponder(503, ""))
+
+		for i := 0; i < 3; i++ {
+			_, err := g.GetCharacter(context.Background(), testCharacterID)
+
+			assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+			assert.NotErrorIs(t, err, ErrCircuitOpen)
+		}
+
+		assert.Equal(t, CircuitOpen, g.CircuitState())
+
+		_, err := g.GetCharacter(context.Background(), testCharacterID)
+
+		var upstreamErr *UpstreamError
+
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+		assert.ErrorAs(t, err, &upstreamErr)
+		assert.Equal(t, time.Minute, upstreamErr.RetryAfter)
+		assert.Equal(t, 3, transport.GetTotalCallCount())
+	})
+
+	t.Run("it does not count successes, client errors or cancellations as failures", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport, _ := newBreakingTestGateway(t, CircuitBreakerConfig{FailureThreshold: 2})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/1",
+			httpmock.NewStringResponder(503, ""))
+		transport.RegisterResponder("GET", defaultBaseURI+"character/2",
+			httpmock.NewStringResponder(200, `{"id": 2}`))
+		transport.RegisterResponder("GET", defaultBaseURI+"character/3",
+			httpmock.NewStringResponder(404, `{"error":"Character not found"}`))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		transport.RegisterResponder("GET", defaultBaseURI+"character/4",
+			func(req *http.Request) (*http.Response, error) {
+				cancel()
+				return nil, context.Canceled
+			})
+
+		for _, id := range []int{1, 2, 1, 3, 1, 4} {
+			_, _ = g.GetCharacter(ctx, id)
+		}
+
+		assert.Equal(t, CircuitClosed, g.CircuitState())
+	})
+
+	t.Run("it closes again after a successful probe once the cool-down passes", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport, clock := newBreakingTestGateway(t, CircuitBreakerConfig{
+			FailureThreshold: 1,
+			CoolDown:         time.Minute,
+		})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			failingResponder(httpmock.NewStringResponder(200, `{"id": 1}`),
+				httpmock.NewStringResponder(503, ""),
+			))
+
+		_, err := g.GetCharacter(context.Background(), testCharacterID)
+		assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+		assert.Equal(t, CircuitOpen, g.CircuitState())
+
+		clock.Advance(time.Minute)
+		assert.Equal(t, CircuitHalfOpen, g.CircuitState())
+
+		result, err := g.GetCharacter(context.Background(), testCharacterID)
+
+		assert.Equal(t, Character{Id: 1}, result)
+		assert.Nil(t, err)
+		assert.Equal(t, CircuitClosed, g.CircuitState())
+	})
+
+	t.Run("it opens again when the probe fails", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport, clock := newBreakingTestGateway(t, CircuitBreakerConfig{
+			FailureThreshold: 1,
+			CoolDown:         time.Minute,
+		})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			httpmock.NewErrorResponder(fmt.Errorf(testErrorText)))
+
+		_, _ = g.GetCharacter(context.Background(), testCharacterID)
+		clock.Advance(time.Minute)
+		_, _ = g.GetCharacter(context.Background(), testCharacterID)
+
+		assert.Equal(t, CircuitOpen, g.CircuitState())
+		assert.Equal(t, 2, transport.GetTotalCallCount())
+	})
+
+	t.Run("it limits the number of half-open probes", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := newCircuitBreaker(CircuitBreakerConfig{
+			FailureThreshold:    1,
+			CoolDown:            time.Minute,
+			HalfOpenMaxRequests: 2,
+		})
+		if err != nil {
+			t.FailNow()
+		}
+
+		clock := &fakeClock{now: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+		b.now = clock.Now
+
+		b.recordFailure()
+		clock.Advance(time.Minute)
+
+		_, first := b.allow()
+		_, second := b.allow()
+		_, third := b.allow()
+
+		assert.True(t, first)
+		assert.True(t, second)
+		assert.False(t, third)
+	})
+
+	t.Run("it does not retry while the circuit is open", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{
+			Retry:          RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond},
+			CircuitBreaker: CircuitBreakerConfig{FailureThreshold: 2, CoolDown: time.Minute},
+		})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			httpmock.NewStringResponder(503, ""))
+
+		_, err := g.GetCharacter(context.Background(), testCharacterID)
+
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.Equal(t, 2, transport.GetTotalCallCount())
+	})
+}
@@ -0,0 +1,232 @@
+package rick_and_morty
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type CoalescingConfig struct {
+	Gateway Gateway
+}
+
+// coalescingGateway shares one in-flight upstream call between concurrent
+// identical calls. The shared call runs detached from any single caller's
+// context, so one client going away does not fail the others; it is only
+// cancelled once every waiting caller has given up.
+type coalescingGateway struct {
+	gateway Gateway
+
+	mu    sync.Mutex
+	calls map[string]*inFlightCall
+}
+
+type inFlightCall struct {
+	done    chan struct{}
+	value   interface{}
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+func NewCoalescingGateway(cfg *CoalescingConfig) (Gateway, error) {
+	switch {
+	case cfg == nil:
+		return nil, fmt.Errorf("missing config parameter")
+	case cfg.Gateway == nil:
+		return nil, fmt.Errorf("missing Gateway parameter")
+	}
+
+	return &coalescingGateway{
+		gateway: cfg.Gateway,
+		calls:   make(map[string]*inFlightCall),
+	}, nil
+}
+
+func (c *coalescingGateway) GetCharacter(ctx context.Context, id int) (Character, error) {
+	return coalesce(c, ctx, characterKeyPrefix+strconv.Itoa(id), func(ctx context.Context) (Character, error) {
+		return c.gateway.GetCharacter(ctx, id)
+	})
+}
+
+func (c *coalescingGateway) GetCharacters(ctx context.Context, ids []int) (CharacterBatch, error) {
+	batch, err := coalesce(c, ctx, charactersKeyPrefix+joinIDs(ids), func(ctx context.Context) (CharacterBatch, error) {
+		return c.gateway.GetCharacters(ctx, ids)
+	})
+
+	return batch.copy(), err
+}
+
+func (c *coalescingGateway) SearchCharacters(ctx context.Context, filter CharacterFilter) ([]Character, error) {
+	characterList, err := coalesce(c, ctx, searchKeyPrefix+filter.query().Encode(), func(ctx context.Context) ([]Character, error) {
+		return c.gateway.SearchCharacters(ctx, filter)
+	})
+
+	return copySlice(characterList), err
+}
+
+func (c *coalescingGateway) ListCharacters(ctx context.Context) ([]Character, error) {
+	characterList, err := coalesce(c, ctx, listKey, func(ctx context.Context) ([]Character, error) {
+		return c.gateway.ListCharacters(ctx)
+	})
+
+	return copySlice(characterList), err
+}
+
+func (c *coalescingGateway) GetEpisode(ctx context.Context, id int) (Episode, error) {
+	return coalesce(c, ctx, episodeKeyPrefix+strconv.Itoa(id), func(ctx context.Context) (Episode, error) {
+		return c.gateway.GetEpisode(ctx, id)
+	})
+}
+
+func (c *coalescingGateway) GetEpisodes(ctx context.Context, ids []int) ([]Episode, error) {
+	episodeList, err := coalesce(c, ctx, episodesKeyPrefix+joinIDs(ids), func(ctx context.Context) ([]Episode, error) {
+		return c.gateway.GetEpisodes(ctx, ids)
+	})
+
+	return copySlice(episodeList), err
+}
+
+func (c *coalescingGateway) SearchEpisodes(ctx context.Context, filter EpisodeFilter) ([]Episode, error) {
+	episodeList, err := coalesce(c, ctx, episodeSearchKeyPrefix+filter.query().Encode(), func(ctx context.Context) ([]Episode, error) {
+		return c.gateway.SearchEpisodes(ctx, filter)
+	})
+
+	return copySlice(episodeList), err
+}
+
+func (c *coalescingGateway) ListEpisodes(ctx context.Context) ([]Episode, error) {
+	episodeList, err := coalesce(c, ctx, episodeListKey, func(ctx context.Context) ([]Episode, error) {
+		return c.gateway.ListEpisodes(ctx)
+	})
+
+	return copySlice(episodeList), err
+}
+
+func (c *coalescingGateway) GetLocation(ctx context.Context, id int) (Location, error) {
+	return coalesce(c, ctx, locationKeyPrefix+strconv.Itoa(id), func(ctx context.Context) (Location, error) {
+		return c.gateway.GetLocation(ctx, id)
+	})
+}
+
+func (c *coalescingGateway) GetLocations(ctx context.Context, ids []int) ([]Location, error) {
+	locationList, err := coalesce(c, ctx, locationsKeyPrefix+joinIDs(ids), func(ctx context.Context) ([]Location, error) {
+		return c.gateway.GetLocations(ctx, ids)
+	})
+
+	return copySlice(locationList), err
+}
+
+func (c *coalescingGateway) SearchLocations(ctx context.Context, filter LocationFilter) ([]Location, error) {
+	locationList, err := coalesce(c, ctx, locationSearchKeyPrefix+filter.query().Encode(), func(ctx context.Context) ([]Location, error) {
+		return c.gateway.SearchLocations(ctx, filter)
+	})
+
+	return copySlice(locationList), err
+}
+
+func (c *coalescingGateway) ListLocations(ctx context.Context) ([]Location, error) {
+	locationList, err := coalesce(c, ctx, locationListKey, func(ctx context.Context) ([]Location, error) {
+		return c.gateway.ListLocations(ctx)
+	})
+
+	return copySlice(locationList), err
+}
+
+// SearchCharacterPages is not coalesced: each iterator advances at its own
+// caller's pace, so there is no single result to share.
+func (c *coalescingGateway) SearchCharacterPages(ctx context.Context, filter CharacterFilter) CharacterIterator {
+	return c.gateway.SearchCharacterPages(ctx, filter)
+}
+
+func (c *coalescingGateway) ListCharacterPages(ctx context.Context) CharacterIterator {
+	return c.gateway.ListCharacterPages(ctx)
+}
+
+func (c *coalescingGateway) CircuitState() CircuitState {
+	return c.gateway.CircuitState()
+}
+
+func (c *coalescingGateway) Close() error {
+	return c.gateway.Close()
+}
+
+// coalesce joins the in-flight call for key, starting one with fetch if
+// there is none, and waits for its result or for ctx to end.
+func coalesce[T any](c *coalescingGateway, ctx context.Context, key string, fetch func(ctx context.Context) (T, error)) (T, error) {
+	c.mu.Lock()
+
+	call, ok := c.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(detachedContext{parent: ctx})
+
+		call = &inFlightCall{
+			done:   make(chan struct{}),
+			cancel: cancel,
+		}
+		c.calls[key] = call
+
+		go func() {
+			value, err := fetch(callCtx)
+
+			c.mu.Lock()
+			call.value, call.err = value, err
+			c.forget(key, call)
+			c.mu.Unlock()
+
+			cancel()
+			close(call.done)
+		}()
+	}
+
+	call.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value.(T), call.err
+	case <-ctx.Done():
+		c.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			c.forget(key, call)
+			call.cancel()
+		}
+		c.mu.Unlock()
+
+		var zero T
+		return zero, transportError(ctx.Err())
+	}
+}
+
+// forget removes call from the in-flight set unless a newer call for the
+// same key has already replaced it. c.mu must be held.
+func (c *coalescingGateway) forget(key string, call *inFlightCall) {
+	if c.calls[key] == call {
+		delete(c.calls, key)
+	}
+}
+
+// detachedContext keeps the values of its parent but none of its deadline or
+// cancellation.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (d detachedContext) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
@@ -0,0 +1,273 @@
+package rick_and_morty
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const testConcurrentCallers = 10
+
+func newCoalescingTestGateway(t *testing.T) (*coalescingGateway, *httpmock.MockTransport) {
+	g, transport := newTestGateway(t, GatewayConfig{})
+
+	c, err := NewCoalescingGateway(&CoalescingConfig{Gateway: g})
+	if err != nil {
+		t.FailNow()
+	}
+
+	return c.(*coalescingGateway), transport
+}
+
+// blockingResponder holds every upstream request until release is closed.
+func blockingResponder(release <-chan struct{}, responder httpmock.Responder) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		select {
+		case <-release:
+			return responder(req)
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// waitForWaiters blocks until n callers are waiting on the in-flight call for key.
+func waitForWaiters(t *testing.T, c *coalescingGateway, key string, n int) {
+	assert.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		call, ok := c.calls[key]
+		return ok && call.waiters == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestCoalescingGateway_NewCoalescingGateway(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns an error when no config passed in", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewCoalescingGateway(nil)
+
+		assert.EqualError(t, fmt.Errorf("missing config parameter"), err.Error())
+	})
+
+	t.Run("it returns an error when no Gateway passed in", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewCoalescingGateway(&CoalescingConfig{})
+
+		assert.EqualError(t, fmt.Errorf("missing Gateway parameter"), err.Error())
+	})
+}
+
+func TestCoalescingGateway_GetCharacter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it makes one upstream call for concurrent identical calls", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c, transport := newCoalescingTestGateway(t)
+
+		release := make(chan struct{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			blockingResponder(release, httpmock.NewStringResponder(200, `{"id": 1, "name": "Rick Sanchez"}`)))
+
+		var wg sync.WaitGroup
+		results := make([]Character, testConcurrentCallers)
+		errs := make([]error, testConcurrentCallers)
+
+		for i := 0; i < testConcurrentCallers; i++ {
+			i := i
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+				results[i], errs[i] = c.GetCharacter(context.Background(), testCharacterID)
+			}()
+		}
+
+		waitForWaiters(t, c, characterKeyPrefix+strconv.Itoa(testCharacterID), testConcurrentCallers)
+		close(release)
+		wg.Wait()
+
+		for i := 0; i < testConcurrentCallers; i++ {
+			assert.Equal(t, Character{Id: 1, Name: "Rick Sanchez"}, results[i])
+			assert.Nil(t, errs[i])
+		}
+
+		assert.Equal(t, 1, transport.GetTotalCallCount())
+	})
+
+	t.Run("it shares the upstream error with every caller", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c, transport := newCoalescingTestGateway(t)
+
+		release := make(chan struct{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			blockingResponder(release, httpmock.NewStringResponder(404, `{"error":"Character not found"}`)))
+
+		var wg sync.WaitGroup
+		errs := make([]error, testConcurrentCallers)
+
+		for i := 0; i < testConcurrentCallers; i++ {
+			i := i
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+				_, errs[i] = c.GetCharacter(context.Background(), testCharacterID)
+			}()
+		}
+
+		waitForWaiters(t, c, characterKeyPrefix+strconv.Itoa(testCharacterID), testConcurrentCallers)
+		close(release)
+		wg.Wait()
+
+		for _, err := range errs {
+			assert.ErrorIs(t, err, ErrNotFound)
+		}
+
+		assert.Equal(t, 1, transport.GetTotalCallCount())
+	})
+
+	t.Run("it keeps the shared call alive when one caller cancels", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c, transport := newCoalescingTestGateway(t)
+
+		release := make(chan struct{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			blockingResponder(release, httpmock.NewStringResponder(200, `{"id": 1}`)))
+
+		cancelledCtx, cancel := context.WithCancel(context.Background())
+
+		var cancelledErr, err error
+		var result Character
+		var wg sync.WaitGroup
+
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			_, cancelledErr = c.GetCharacter(cancelledCtx, testCharacterID)
+		}()
+
+		go func() {
+			defer wg.Done()
+			result, err = c.GetCharacter(context.Background(), testCharacterID)
+		}()
+
+		waitForWaiters(t, c, characterKeyPrefix+strconv.Itoa(testCharacterID), 2)
+		cancel()
+		waitForWaiters(t, c, characterKeyPrefix+strconv.Itoa(testCharacterID), 1)
+		close(release)
+		wg.Wait()
+
+		assert.ErrorIs(t, cancelledErr, context.Canceled)
+		assert.Equal(t, Character{Id: 1}, result)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, transport.GetTotalCallCount())
+	})
+
+	t.Run("it cancels the upstream call once every caller has given up", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c, transport := newCoalescingTestGateway(t)
+
+		upstreamCancelled := make(chan struct{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			func(req *http.Request) (*http.Response, error) {
+				<-req.Context().Done()
+				close(upstreamCancelled)
+				return nil, req.Context().Err()
+			})
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		done := make(chan error)
+
+		go func() {
+			_, err := c.GetCharacter(ctx, testCharacterID)
+			done <- err
+		}()
+
+		waitForWaiters(t, c, characterKeyPrefix+strconv.Itoa(testCharacterID), 1)
+		cancel()
+
+		assert.ErrorIs(t, <-done, context.Canceled)
+
+		select {
+		case <-upstreamCancelled:
+		case <-time.After(time.Second):
+			t.Error("upstream call was not cancelled")
+		}
+	})
+}
+
+func TestCoalescingGateway_ListCharacters(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it makes one upstream call per page for concurrent list calls", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c, transport := newCoalescingTestGateway(t)
+
+		release := make(chan struct{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character",
+			blockingResponder(release, func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, CharactersListResponse{
+					Info:    ApiInfo{Count: 2, Pages: 1},
+					Results: []Character{{Id: 1}, {Id: 2}},
+				})
+			}))
+
+		var wg sync.WaitGroup
+		results := make([][]Character, testConcurrentCallers)
+
+		for i := 0; i < testConcurrentCallers; i++ {
+			i := i
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+				results[i], _ = c.ListCharacters(context.Background())
+			}()
+		}
+
+		waitForWaiters(t, c, listKey, testConcurrentCallers)
+		close(release)
+		wg.Wait()
+
+		for _, result := range results {
+			assert.Equal(t, []Character{{Id: 1}, {Id: 2}}, result)
+		}
+
+		assert.Equal(t, 1, transport.GetTotalCallCount())
+	})
+}
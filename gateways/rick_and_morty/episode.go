@@ -0,0 +1,59 @@
+package rick_and_morty
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+func (g *gateway) GetEpisode(ctx context.Context, id int) (Episode, error) {
+	apiData := Episode{}
+
+	err := g.getJSON(ctx, g.baseURI+"episode/"+strconv.Itoa(id), &apiData)
+	if err != nil {
+		return Episode{}, err
+	}
+
+	return apiData, nil
+}
+
+func (g *gateway) GetEpisodes(ctx context.Context, ids []int) ([]Episode, error) {
+	episodeList, _, err := getMany(ctx, g, "episode", ids, func(e Episode) int { return e.Id })
+	if err != nil {
+		return []Episode{}, err
+	}
+
+	return episodeList, nil
+}
+
+func (g *gateway) SearchEpisodes(ctx context.Context, filter EpisodeFilter) ([]Episode, error) {
+	episodeList, err := getAllData[Episode](ctx, g, g.baseURI+"episode?"+filter.query().Encode())
+	if err != nil {
+		return []Episode{}, err
+	}
+
+	return episodeList, nil
+}
+
+func (g *gateway) ListEpisodes(ctx context.Context) ([]Episode, error) {
+	episodeList, err := getAllData[Episode](ctx, g, g.baseURI+"episode")
+	if err != nil {
+		return []Episode{}, err
+	}
+
+	return episodeList, nil
+}
+
+func (f EpisodeFilter) query() url.Values {
+	query := url.Values{}
+
+	if f.Name != "" {
+		query.Set("name", f.Name)
+	}
+
+	if f.Episode != "" {
+		query.Set("episode", f.Episode)
+	}
+
+	return query
+}
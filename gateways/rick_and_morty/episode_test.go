@@ -0,0 +1,269 @@
+package rick_and_morty
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testEpisodeID              = 1
+	testMultipleEpisodeIDsPath = "1,2"
+)
+
+var testMultipleEpisodeIDs = []int{1, 2}
+
+func testEpisodes() []Episode {
+	pilotTime, _ := time.Parse(time.RFC3339, "2017-11-10T12:56:33.798Z")
+	lawnMowerDogTime, _ := time.Parse(time.RFC3339, "2017-11-10T12:56:33.916Z")
+
+	return []Episode{
+		{
+			Id:         1,
+			Name:       "Pilot",
+			AirDate:    "December 2, 2013",
+			Episode:    "S01E01",
+			Characters: []string{"https://rickandmortyapi.com/api/character/1"},
+			Url:        "https://rickandmortyapi.com/api/episode/1",
+			Created:    pilotTime,
+		},
+		{
+			Id:         2,
+			Name:       "Lawnmower Dog",
+			AirDate:    "December 9, 2013",
+			Episode:    "S01E02",
+			Characters: []string{"https://rickandmortyapi.com/api/character/1"},
+			Url:        "https://rickandmortyapi.com/api/episode/2",
+			Created:    lawnMowerDogTime,
+		},
+	}
+}
+
+func TestGateway_GetEpisode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns an error if the API returns an error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"episode/"+strconv.Itoa(testEpisodeID),
+			httpmock.NewErrorResponder(fmt.Errorf(testErrorText)))
+
+		result, err := g.GetEpisode(context.Background(), testEpisodeID)
+
+		assert.Equal(t, Episode{}, result)
+		assert.Equal(t, "Get \"https://rickandmortyapi.com/api/episode/1\": an error", err.Error())
+	})
+
+	t.Run("it returns ErrNotFound if the episode does not exist", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"episode/"+strconv.Itoa(testEpisodeID),
+			httpmock.NewStringResponder(404, `{"error":"Episode not found"}`))
+
+		result, err := g.GetEpisode(context.Background(), testEpisodeID)
+
+		assert.Equal(t, Episode{}, result)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("it successfully returns an Episode", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		expectedEpisode := testEpisodes()[0]
+
+		transport.RegisterResponder("GET", defaultBaseURI+"episode/"+strconv.Itoa(testEpisodeID),
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, expectedEpisode)
+			})
+
+		result, err := g.GetEpisode(context.Background(), testEpisodeID)
+
+		assert.Equal(t, expectedEpisode, result)
+		assert.Nil(t, err)
+	})
+}
+
+func TestGateway_GetEpisodes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns an error if decoding the API response returns an error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"episode/"+testMultipleEpisodeIDsPath,
+			httpmock.NewStringResponder(200, `{"id": "one"}`))
+
+		result, err := g.GetEpisodes(context.Background(), testMultipleEpisodeIDs)
+
+		assert.Equal(t, []Episode{}, result)
+		assert.ErrorIs(t, err, ErrDecode)
+	})
+
+	t.Run("it successfully returns a list of episodes", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		expectedEpisodes := testEpisodes()
+
+		transport.RegisterResponder("GET", defaultBaseURI+"episode/"+testMultipleEpisodeIDsPath,
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, expectedEpisodes)
+			})
+
+		result, err := g.GetEpisodes(context.Background(), testMultipleEpisodeIDs)
+
+		assert.Equal(t, expectedEpisodes, result)
+		assert.Nil(t, err)
+	})
+}
+
+func TestGateway_SearchEpisodes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns an empty list when the filter matches nothing", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"episode?name=zzz",
+			httpmock.NewStringResponder(404, `{"error":"There is nothing here"}`))
+
+		result, err := g.SearchEpisodes(context.Background(), EpisodeFilter{Name: "zzz"})
+
+		assert.Equal(t, []Episode{}, result)
+		assert.Nil(t, err)
+	})
+
+	t.Run("it returns an error if the API returns an error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"episode?episode=S01",
+			httpmock.NewStringResponder(500, ""))
+
+		result, err := g.SearchEpisodes(context.Background(), EpisodeFilter{Episode: "S01"})
+
+		assert.Equal(t, []Episode{}, result)
+		assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+	})
+
+	t.Run("it encodes every filter into the query string", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		expectedEpisode := testEpisodes()[1]
+
+		transport.RegisterResponder("GET", defaultBaseURI+"episode?episode=S01E02&name=Lawnmower+Dog",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, listResponse[Episode]{
+					Info:    ApiInfo{Count: 1, Pages: 1},
+					Results: []Episode{expectedEpisode},
+				})
+			})
+
+		result, err := g.SearchEpisodes(context.Background(), EpisodeFilter{Name: "Lawnmower Dog", Episode: "S01E02"})
+
+		assert.Equal(t, []Episode{expectedEpisode}, result)
+		assert.Nil(t, err)
+	})
+}
+
+func TestGateway_ListEpisodes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns ErrNotFound when the unfiltered listing is missing", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"episode",
+			httpmock.NewStringResponder(404, `{"error":"There is nothing here"}`))
+
+		result, err := g.ListEpisodes(context.Background())
+
+		assert.Equal(t, []Episode{}, result)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("it returns an error if the API returns an error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"episode",
+			httpmock.NewErrorResponder(fmt.Errorf(testErrorText)))
+
+		result, err := g.ListEpisodes(context.Background())
+
+		assert.Equal(t, []Episode{}, result)
+		assert.Equal(t, "Get \"https://rickandmortyapi.com/api/episode\": an error", err.Error())
+	})
+
+	t.Run("it successfully returns every page of episodes", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		expectedEpisodes := testEpisodes()
+
+		transport.RegisterResponder("GET", defaultBaseURI+"episode",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, listResponse[Episode]{
+					Info:    ApiInfo{Count: 2, Pages: 2},
+					Results: expectedEpisodes[:1],
+				})
+			})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"episode?page=2",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, listResponse[Episode]{
+					Info:    ApiInfo{Count: 2, Pages: 2},
+					Results: expectedEpisodes[1:],
+				})
+			})
+
+		result, err := g.ListEpisodes(context.Background())
+
+		assert.Equal(t, expectedEpisodes, result)
+		assert.Nil(t, err)
+	})
+}
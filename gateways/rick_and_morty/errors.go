@@ -0,0 +1,108 @@
+package rick_and_morty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrNotFound            = errors.New("not found")
+	ErrBadRequest          = errors.New("bad request")
+	ErrRateLimited         = errors.New("rate limited")
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	ErrUpstreamTimeout     = errors.New("upstream timeout")
+	ErrDecode              = errors.New("decode failure")
+
+	// ErrCircuitOpen is returned without calling the upstream while the
+	// circuit breaker is open. It also matches ErrUpstreamUnavailable.
+	ErrCircuitOpen = errors.New("upstream unavailable: circuit open")
+)
+
+// UpstreamError describes a failed call to the upstream API. Kind is one of
+// the Err* values above, so callers can branch on it with errors.Is, while
+// errors.Is/As also reach the underlying transport or decoding error.
+type UpstreamError struct {
+	Kind       error
+	StatusCode int           // Zero unless the upstream answered with a non-2xx status.
+	Message    string        // The upstream's own error message, if it sent one.
+	RetryAfter time.Duration // From the upstream's Retry-After header, if any.
+	Err        error
+}
+
+func (e *UpstreamError) Error() string {
+	switch {
+	case e.StatusCode != 0:
+		message := e.Message
+		if message == "" {
+			message = http.StatusText(e.StatusCode)
+		}
+		return fmt.Sprintf("upstream responded with %d: %s", e.StatusCode, message)
+	case e.Kind == ErrDecode:
+		return fmt.Sprintf("%s: %s", ErrDecode, e.Err)
+	case e.Err != nil:
+		return e.Err.Error()
+	default:
+		return e.Kind.Error()
+	}
+}
+
+func (e *UpstreamError) Is(target error) bool {
+	return target == e.Kind || (e.Kind == ErrCircuitOpen && target == ErrUpstreamUnavailable)
+}
+
+func (e *UpstreamError) Unwrap() error {
+	return e.Err
+}
+
+// statusError builds the error for a non-2xx upstream response. message is
+// the "error" field of the upstream body, when present.
+func statusError(statusCode int, message string, retryAfter time.Duration) error {
+	var kind error
+
+	switch {
+	case statusCode == http.StatusNotFound:
+		kind = ErrNotFound
+	case statusCode == http.StatusTooManyRequests:
+		kind = ErrRateLimited
+	case statusCode == http.StatusGatewayTimeout:
+		kind = ErrUpstreamTimeout
+	case statusCode >= 400 && statusCode < 500:
+		kind = ErrBadRequest
+	default:
+		kind = ErrUpstreamUnavailable
+	}
+
+	return &UpstreamError{
+		Kind:       kind,
+		StatusCode: statusCode,
+		Message:    message,
+		RetryAfter: retryAfter,
+	}
+}
+
+// transportError wraps an error returned before any upstream response was
+// received, telling timeouts apart from other connection failures.
+func transportError(err error) error {
+	kind := ErrUpstreamUnavailable
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		kind = ErrUpstreamTimeout
+	}
+
+	return &UpstreamError{
+		Kind: kind,
+		Err:  err,
+	}
+}
+
+func decodeError(err error) error {
+	return &UpstreamError{
+		Kind: ErrDecode,
+		Err:  err,
+	}
+}
@@ -1,43 +1,140 @@
 package rick_and_morty
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gojo/utilities"
 )
 
-const baseURI = "https://rickandmortyapi.com/api/"
+const (
+	defaultBaseURI            = "https://rickandmortyapi.com/api/"
+	defaultMaxConcurrentPages = 4
+	defaultMaxBatchSize       = 100
+)
 
 type GatewayConfig struct {
-	//HttpClient utilities.HttpClient // If using a custom HTTP Client.
+	HttpClient utilities.HttpClient // If using a custom HTTP Client, the settings below are ignored.
+	BaseURL    string               // Defaults to the public rickandmortyapi.com API.
+
+	MaxConcurrentPages int // Upper bound on listing pages or multi-get batches fetched in parallel; defaults to 4.
+	MaxBatchSize       int // Most ids sent in one multi-get request; larger sets are split. Defaults to 100.
+
+	Retry          RetryPolicy // Applied to every upstream request, including each listing page.
+	CircuitBreaker CircuitBreakerConfig
+	RateLimit      RateLimitConfig // Shared by every method and page of this gateway.
+
+	Timeout             time.Duration
+	ProxyURL            string
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	TLSHandshakeTimeout time.Duration
 }
 
 type gateway struct {
-	//httpClient utilities.HttpClient
+	httpClient         utilities.HttpClient
+	baseURI            string
+	maxConcurrentPages int
+	maxBatchSize       int
+	retrier            *retrier
+	breaker            *circuitBreaker
+	limiter            *rateLimiter
 }
 
 func NewGateway(cfg *GatewayConfig) (Gateway, error) {
 	switch {
 	case cfg == nil:
 		return nil, fmt.Errorf("missing config parameter")
-		//case cfg.HttpClient == nil:
-		//	return nil, fmt.Errorf("missing HttpClient parameter")
+	case cfg.MaxConcurrentPages < 0:
+		return nil, fmt.Errorf("invalid MaxConcurrentPages parameter")
+	case cfg.MaxBatchSize < 0:
+		return nil, fmt.Errorf("invalid MaxBatchSize parameter")
+	}
+
+	baseURI, err := parseBaseURL(cfg.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	retrier, err := newRetrier(cfg.Retry)
+	if err != nil {
+		return nil, err
+	}
+
+	breaker, err := newCircuitBreaker(cfg.CircuitBreaker)
+	if err != nil {
+		return nil, err
+	}
+
+	limiter, err := newRateLimiter(cfg.RateLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := cfg.HttpClient
+	if httpClient == nil {
+		httpClient, err = utilities.NewHttpClient(&utilities.HttpClientConfig{
+			Timeout:             cfg.Timeout,
+			ProxyURL:            cfg.ProxyURL,
+			MaxIdleConns:        cfg.MaxIdleConns,
+			MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+			IdleConnTimeout:     cfg.IdleConnTimeout,
+			TLSHandshakeTimeout: cfg.TLSHandshakeTimeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	maxConcurrentPages := cfg.MaxConcurrentPages
+	if maxConcurrentPages == 0 {
+		maxConcurrentPages = defaultMaxConcurrentPages
+	}
+
+	maxBatchSize := cfg.MaxBatchSize
+	if maxBatchSize == 0 {
+		maxBatchSize = defaultMaxBatchSize
 	}
 
 	return &gateway{
-		//httpClient: cfg.HttpClient,
+		httpClient:         httpClient,
+		baseURI:            baseURI,
+		maxConcurrentPages: maxConcurrentPages,
+		maxBatchSize:       maxBatchSize,
+		retrier:            retrier,
+		breaker:            breaker,
+		limiter:            limiter,
 	}, nil
 }
 
-func (g *gateway) GetCharacter(id string) (Character, error) {
-	apiResponse, err := http.Get(baseURI + "character/" + id)
-	if err != nil {
-		return Character{}, err
+// parseBaseURL validates a configured base URL and normalises it to end in a
+// slash, so resource paths can be appended directly.
+func parseBaseURL(baseURL string) (string, error) {
+	if baseURL == "" {
+		return defaultBaseURI, nil
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid BaseURL parameter")
 	}
 
+	return strings.TrimSuffix(baseURL, "/") + "/", nil
+}
+
+func (g *gateway) GetCharacter(ctx context.Context, id int) (Character, error) {
 	apiData := Character{}
 
-	err = json.NewDecoder(apiResponse.Body).Decode(&apiData)
+	err := g.getJSON(ctx, g.baseURI+"character/"+strconv.Itoa(id), &apiData)
 	if err != nil {
 		return Character{}, err
 	}
@@ -45,24 +142,26 @@ func (g *gateway) GetCharacter(id string) (Character, error) {
 	return apiData, nil
 }
 
-func (g *gateway) GetCharacters(ids string) ([]Character, error) {
-	apiResponse, err := http.Get(baseURI + "character/" + ids)
+func (g *gateway) GetCharacters(ctx context.Context, ids []int) (CharacterBatch, error) {
+	characters, missing, err := getMany(ctx, g, "character", ids, func(c Character) int { return c.Id })
 	if err != nil {
-		return []Character{}, err
+		return CharacterBatch{Characters: []Character{}}, err
 	}
 
-	var apiData []Character
+	return CharacterBatch{Characters: characters, Missing: missing}, nil
+}
 
-	err = json.NewDecoder(apiResponse.Body).Decode(&apiData)
+func (g *gateway) SearchCharacters(ctx context.Context, filter CharacterFilter) ([]Character, error) {
+	characterList, err := getAllData[Character](ctx, g, g.baseURI+"character?"+filter.query().Encode())
 	if err != nil {
 		return []Character{}, err
 	}
 
-	return apiData, nil
+	return characterList, nil
 }
 
-func (g *gateway) SearchCharacters(name string) ([]Character, error) {
-	characterList, err := g.getAllData(baseURI + "character?name=" + name)
+func (g *gateway) ListCharacters(ctx context.Context) ([]Character, error) {
+	characterList, err := getAllData[Character](ctx, g, g.baseURI+"character")
 	if err != nil {
 		return []Character{}, err
 	}
@@ -70,67 +169,253 @@ func (g *gateway) SearchCharacters(name string) ([]Character, error) {
 	return characterList, nil
 }
 
-func (g *gateway) ListCharacters() ([]Character, error) {
-	characterList, err := g.getAllData(baseURI + "character")
-	if err != nil {
-		return []Character{}, err
+func (f CharacterFilter) query() url.Values {
+	query := url.Values{}
+
+	if f.Name != "" {
+		query.Set("name", f.Name)
 	}
 
-	return characterList, nil
+	if f.Status != "" {
+		query.Set("status", f.Status)
+	}
+
+	if f.Species != "" {
+		query.Set("species", f.Species)
+	}
+
+	if f.Type != "" {
+		query.Set("type", f.Type)
+	}
+
+	if f.Gender != "" {
+		query.Set("gender", f.Gender)
+	}
+
+	return query
+}
+
+// joinIDs formats ids for the upstream multi-get endpoints, e.g. "1,2,3".
+func joinIDs(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func (g *gateway) CircuitState() CircuitState {
+	return g.breaker.State()
+}
+
+func (g *gateway) Close() error {
+	if client, ok := g.httpClient.(interface{ CloseIdleConnections() }); ok {
+		client.CloseIdleConnections()
+	}
+
+	return nil
+}
+
+// getJSON issues a GET request bound to ctx and decodes a successful response
+// into v, retrying failures according to the gateway's RetryPolicy. Every
+// attempt passes through the rate limiter and then the circuit breaker.
+// Non-2xx responses, transport failures, malformed bodies, an open circuit
+// and an exhausted rate limit are all reported as *UpstreamError. When ctx
+// carries a CachedGateway revalidation for url, the request is conditional
+// and an upstream 304 is reported as errNotModified.
+func (g *gateway) getJSON(ctx context.Context, url string, v interface{}) error {
+	return g.retrier.do(ctx, func() error {
+		return g.limiter.call(ctx, func() error {
+			return g.breaker.call(ctx, func() error {
+				return g.getJSONOnce(ctx, url, v)
+			})
+		})
+	})
 }
 
-func (g *gateway) getAllData(url string) ([]Character, error) {
-	var allData []Character
+// getJSONOnce makes a single attempt at getJSON, always closing the
+// response body.
+func (g *gateway) getJSONOnce(ctx context.Context, url string, v interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return transportError(err)
+	}
 
-	apiResponse, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return allData, err
+		return err
 	}
 
-	apiData := CharactersListResponse{}
+	revalidation := revalidationFrom(ctx)
+	conditional := revalidation.conditional(req, url)
 
-	err = json.NewDecoder(apiResponse.Body).Decode(&apiData)
+	apiResponse, err := g.httpClient.Do(req)
 	if err != nil {
-		return []Character{}, err
+		return transportError(err)
+	}
+	defer apiResponse.Body.Close()
+
+	if apiResponse.StatusCode == http.StatusNotModified && conditional {
+		revalidation.notModified(apiResponse.Header)
+		return errNotModified
+	}
+
+	if apiResponse.StatusCode < 200 || apiResponse.StatusCode > 299 {
+		apiError := struct {
+			Error string `json:"error"`
+		}{}
+		_ = json.NewDecoder(apiResponse.Body).Decode(&apiError)
+
+		retryAfter := parseRetryAfter(apiResponse.Header.Get("Retry-After"), time.Now())
+
+		return statusError(apiResponse.StatusCode, apiError.Error, retryAfter)
+	}
+
+	err = json.NewDecoder(apiResponse.Body).Decode(v)
+	if err != nil {
+		return decodeError(err)
+	}
+
+	revalidation.record(url, apiResponse.Header)
+
+	return nil
+}
+
+// getAllData fetches the first page of a listing to learn the page count,
+// then fetches the remaining pages in parallel using at most
+// g.maxConcurrentPages workers. Results are returned in page order, and the
+// first failing page cancels the pages still in flight. A filtered listing
+// that matches nothing is returned as empty; see emptyListing.
+func getAllData[T any](ctx context.Context, g *gateway, firstPageURL string) ([]T, error) {
+	apiData := listResponse[T]{}
+
+	err := g.getJSON(ctx, firstPageURL, &apiData)
+	if emptyListing(firstPageURL, err) {
+		return []T{}, nil
+	}
+	if err != nil {
+		return []T{}, err
 	}
 
 	totalPages := apiData.Info.Pages
-	if totalPages == 1 {
+	if totalPages <= 1 {
 		return apiData.Results, nil
 	}
 
-	if apiData.Results != nil {
-		for _, c := range apiData.Results {
-			allData = append(allData, c)
+	pages := make([][]T, totalPages)
+	pages[0] = apiData.Results
+
+	err = runParallel(ctx, totalPages-1, g.maxConcurrentPages, func(ctx context.Context, i int) error {
+		page := i + 2
+		pageData := listResponse[T]{}
+
+		err := g.getJSON(ctx, pageURL(firstPageURL, page), &pageData)
+		if err != nil {
+			return err
 		}
+
+		pages[page-1] = pageData.Results
+		return nil
+	})
+	if err != nil {
+		return []T{}, err
 	}
 
-	nextBatch := ""
-	if apiData.Info.Next != "" {
-		nextBatch = apiData.Info.Next
+	allData := make([]T, 0, apiData.Info.Count)
+	for _, results := range pages {
+		allData = append(allData, results...)
 	}
 
-	for i := 1; i < totalPages; i++ {
-		apiResponse, err = http.Get(nextBatch)
-		if err != nil {
-			return allData, err
-		}
+	return allData, nil
+}
 
-		apiData = CharactersListResponse{}
+// runParallel calls fn for every index in [0, n) using at most workers
+// goroutines. The first error cancels the calls still pending or in flight
+// and is returned; cancellation of ctx itself is reported as a transport
+// error.
+func runParallel(ctx context.Context, n, workers int, fn func(ctx context.Context, i int) error) error {
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-		err = json.NewDecoder(apiResponse.Body).Decode(&apiData)
-		if err != nil {
-			continue
-		}
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	if workers > n {
+		workers = n
+	}
 
-		if apiData.Results != nil {
-			for _, c := range apiData.Results {
-				allData = append(allData, c)
+	indexes := make(chan int)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				err := fn(workerCtx, i)
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
 			}
+		}()
+	}
+
+dispatch:
+	for i := 0; i < n; i++ {
+		select {
+		case indexes <- i:
+		case <-workerCtx.Done():
+			break dispatch
 		}
+	}
+
+	close(indexes)
+	wg.Wait()
 
-		nextBatch = apiData.Info.Next
+	if firstErr != nil {
+		return firstErr
 	}
 
-	return allData, nil
+	if err := ctx.Err(); err != nil {
+		return transportError(err)
+	}
+
+	return nil
+}
+
+// emptyListing reports whether err, from fetching the first page of the
+// listing at firstPageURL, means the listing has no results. The upstream
+// answers a filter that matches nothing with a 404 rather than an empty page,
+// but a 404 for an unfiltered listing means the URL itself is wrong and is
+// left as an error.
+func emptyListing(firstPageURL string, err error) bool {
+	if !errors.Is(err, ErrNotFound) {
+		return false
+	}
+
+	u, parseErr := url.Parse(firstPageURL)
+
+	return parseErr == nil && u.RawQuery != ""
+}
+
+// pageURL returns listingURL with its page query parameter set to page,
+// keeping any other filters already present.
+func pageURL(listingURL string, page int) string {
+	u, err := url.Parse(listingURL)
+	if err != nil {
+		return listingURL
+	}
+
+	query := u.Query()
+	query.Set("page", strconv.Itoa(page))
+	u.RawQuery = query.Encode()
+
+	return u.String()
 }
@@ -1,8 +1,13 @@
 package rick_and_morty
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -12,12 +17,30 @@ import (
 )
 
 const (
-	testCharacterID          = "1"
-	testErrorText            = "an error"
-	testMultipleCharacterIDs = "1,2"
-	testSearchCharacterQuery = "Rick"
+	testCharacterID              = 1
+	testErrorText                = "an error"
+	testMultipleCharacterIDsPath = "1,2"
+	testSearchCharacterQuery     = "Rick"
 )
 
+var testMultipleCharacterIDs = []int{1, 2}
+
+// newTestGateway builds a gateway from cfg whose upstream is a fresh mock
+// transport, for the test to register its responders on. Any HttpClient in
+// cfg is replaced.
+func newTestGateway(t *testing.T, cfg GatewayConfig) (*gateway, *httpmock.MockTransport) {
+	transport := httpmock.NewMockTransport()
+
+	cfg.HttpClient = &http.Client{Transport: transport}
+
+	g, err := NewGateway(&cfg)
+	if err != nil {
+		t.FailNow()
+	}
+
+	return g.(*gateway), transport
+}
+
 func TestGateway_NewGateway(t *testing.T) {
 	t.Parallel()
 
@@ -38,45 +61,71 @@ func TestGateway_NewGateway(t *testing.T) {
 
 		assert.Nil(t, err)
 	})
+
+	t.Run("it returns an error when an invalid BaseURL is passed in", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewGateway(&GatewayConfig{BaseURL: "localhost:8080"})
+
+		assert.EqualError(t, fmt.Errorf("invalid BaseURL parameter"), err.Error())
+	})
+
+	t.Run("it returns an error when an invalid ProxyURL is passed in", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewGateway(&GatewayConfig{ProxyURL: "http://[::1"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("it sends requests to the configured BaseURL", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{
+			BaseURL: "http://localhost:8080/api",
+		})
+
+		transport.RegisterResponder("GET", "http://localhost:8080/api/character/"+strconv.Itoa(testCharacterID),
+			httpmock.NewStringResponder(200, `{"id": 1}`))
+
+		result, err := g.GetCharacter(context.Background(), testCharacterID)
+
+		assert.Equal(t, Character{Id: 1}, result)
+		assert.Nil(t, err)
+	})
 }
 
 func TestGateway_GetCharacter(t *testing.T) {
+	t.Parallel()
+
 	t.Run("it returns an error if the API returns an error", func(t *testing.T) {
+		t.Parallel()
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		g, err := NewGateway(&GatewayConfig{})
-		if err != nil {
-			t.FailNow()
-		}
-
-		httpmock.Activate()
-		defer httpmock.DeactivateAndReset()
+		g, transport := newTestGateway(t, GatewayConfig{})
 
-		httpmock.RegisterResponder("GET", baseURI+"character/"+testCharacterID,
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
 			func(req *http.Request) (*http.Response, error) {
 				return nil, fmt.Errorf(testErrorText)
 			})
 
-		result, err := g.GetCharacter(testCharacterID)
+		result, err := g.GetCharacter(context.Background(), testCharacterID)
 
 		assert.Equal(t, Character{}, result)
 		assert.Equal(t, "Get \"https://rickandmortyapi.com/api/character/1\": an error", err.Error())
 	})
 
 	t.Run("it returns an error if decoding the API response returns an error", func(t *testing.T) {
+		t.Parallel()
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		g, err := NewGateway(&GatewayConfig{})
-		if err != nil {
-			t.FailNow()
-		}
-
-		httpmock.Activate()
-		defer httpmock.DeactivateAndReset()
+		g, transport := newTestGateway(t, GatewayConfig{})
 
-		httpmock.RegisterResponder("GET", baseURI+"character/"+testCharacterID,
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
 			func(req *http.Request) (*http.Response, error) {
 				resp, err := httpmock.NewJsonResponse(200, `{"Name": "Foo`+"\u001a"+`"}`)
 				if err != nil {
@@ -85,23 +134,18 @@ func TestGateway_GetCharacter(t *testing.T) {
 				return resp, nil
 			})
 
-		result, err := g.GetCharacter(testCharacterID)
+		result, err := g.GetCharacter(context.Background(), testCharacterID)
 
 		assert.Equal(t, Character{}, result)
 		assert.Error(t, err)
 	})
 
 	t.Run("it successfully returns a Character", func(t *testing.T) {
+		t.Parallel()
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		g, err := NewGateway(&GatewayConfig{})
-		if err != nil {
-			t.FailNow()
-		}
-
-		httpmock.Activate()
-		defer httpmock.DeactivateAndReset()
+		g, transport := newTestGateway(t, GatewayConfig{})
 
 		expectedTime, _ := time.Parse(time.RFC3339, "2017-11-04T18:48:46.250Z")
 		expectedCharacter := Character{
@@ -116,7 +160,7 @@ func TestGateway_GetCharacter(t *testing.T) {
 			Created: expectedTime,
 		}
 
-		httpmock.RegisterResponder("GET", baseURI+"character/"+testCharacterID,
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
 			func(req *http.Request) (*http.Response, error) {
 				resp, err := httpmock.NewJsonResponse(200, expectedCharacter)
 				if err != nil {
@@ -125,7 +169,7 @@ func TestGateway_GetCharacter(t *testing.T) {
 				return resp, nil
 			})
 
-		result, err := g.GetCharacter(testCharacterID)
+		result, err := g.GetCharacter(context.Background(), testCharacterID)
 
 		assert.Equal(t, expectedCharacter, result)
 		assert.Nil(t, err)
@@ -133,42 +177,48 @@ func TestGateway_GetCharacter(t *testing.T) {
 }
 
 func TestGateway_GetCharacters(t *testing.T) {
-	t.Run("it returns an error if the API returns an error", func(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it does not call the API when no ids are passed in", func(t *testing.T) {
+		t.Parallel()
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		g, err := NewGateway(&GatewayConfig{})
-		if err != nil {
-			t.FailNow()
-		}
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		result, err := g.GetCharacters(context.Background(), nil)
+
+		assert.Equal(t, CharacterBatch{Characters: []Character{}}, result)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, transport.GetTotalCallCount())
+	})
+
+	t.Run("it returns an error if the API returns an error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
 
-		httpmock.Activate()
-		defer httpmock.DeactivateAndReset()
+		g, transport := newTestGateway(t, GatewayConfig{})
 
-		httpmock.RegisterResponder("GET", baseURI+"character/"+testMultipleCharacterIDs,
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+testMultipleCharacterIDsPath,
 			func(req *http.Request) (*http.Response, error) {
 				return nil, fmt.Errorf(testErrorText)
 			})
 
-		result, err := g.GetCharacters(testMultipleCharacterIDs)
+		result, err := g.GetCharacters(context.Background(), testMultipleCharacterIDs)
 
-		assert.Equal(t, []Character{}, result)
+		assert.Equal(t, CharacterBatch{Characters: []Character{}}, result)
 		assert.Equal(t, "Get \"https://rickandmortyapi.com/api/character/1,2\": an error", err.Error())
 	})
 
 	t.Run("it returns an error if decoding the API response returns an error", func(t *testing.T) {
+		t.Parallel()
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		g, err := NewGateway(&GatewayConfig{})
-		if err != nil {
-			t.FailNow()
-		}
-
-		httpmock.Activate()
-		defer httpmock.DeactivateAndReset()
+		g, transport := newTestGateway(t, GatewayConfig{})
 
-		httpmock.RegisterResponder("GET", baseURI+"character/"+testMultipleCharacterIDs,
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+testMultipleCharacterIDsPath,
 			func(req *http.Request) (*http.Response, error) {
 				resp, err := httpmock.NewJsonResponse(200, `{"Name": "Foo`+"\u001a"+`"}`)
 				if err != nil {
@@ -177,23 +227,18 @@ func TestGateway_GetCharacters(t *testing.T) {
 				return resp, nil
 			})
 
-		result, err := g.GetCharacters(testMultipleCharacterIDs)
+		result, err := g.GetCharacters(context.Background(), testMultipleCharacterIDs)
 
-		assert.Equal(t, []Character{}, result)
+		assert.Equal(t, CharacterBatch{Characters: []Character{}}, result)
 		assert.Error(t, err)
 	})
 
 	t.Run("it successfully returns a list of characters", func(t *testing.T) {
+		t.Parallel()
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		g, err := NewGateway(&GatewayConfig{})
-		if err != nil {
-			t.FailNow()
-		}
-
-		httpmock.Activate()
-		defer httpmock.DeactivateAndReset()
+		g, transport := newTestGateway(t, GatewayConfig{})
 
 		expectedRickTime, _ := time.Parse(time.RFC3339, "2017-11-04T18:48:46.250Z")
 		expectedMortyTime, _ := time.Parse(time.RFC3339, "2017-11-04T18:50:21.651Z")
@@ -223,7 +268,7 @@ func TestGateway_GetCharacters(t *testing.T) {
 			},
 		}
 
-		httpmock.RegisterResponder("GET", baseURI+"character/"+testMultipleCharacterIDs,
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+testMultipleCharacterIDsPath,
 			func(req *http.Request) (*http.Response, error) {
 				resp, err := httpmock.NewJsonResponse(200, expectedCharacters)
 				if err != nil {
@@ -232,50 +277,58 @@ func TestGateway_GetCharacters(t *testing.T) {
 				return resp, nil
 			})
 
-		result, err := g.GetCharacters(testMultipleCharacterIDs)
+		result, err := g.GetCharacters(context.Background(), testMultipleCharacterIDs)
 
-		assert.Equal(t, expectedCharacters, result)
+		assert.Equal(t, CharacterBatch{Characters: expectedCharacters}, result)
 		assert.Nil(t, err)
 	})
 }
 
 func TestGateway_SearchCharacters(t *testing.T) {
-	t.Run("it returns an error if the API returns an error", func(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns an empty list when the filter matches nothing", func(t *testing.T) {
+		t.Parallel()
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		g, err := NewGateway(&GatewayConfig{})
-		if err != nil {
-			t.FailNow()
-		}
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character?name=zzz",
+			httpmock.NewStringResponder(404, `{"error":"There is nothing here"}`))
+
+		result, err := g.SearchCharacters(context.Background(), CharacterFilter{Name: "zzz"})
+
+		assert.Equal(t, []Character{}, result)
+		assert.Nil(t, err)
+	})
+
+	t.Run("it returns an error if the API returns an error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
 
-		httpmock.Activate()
-		defer httpmock.DeactivateAndReset()
+		g, transport := newTestGateway(t, GatewayConfig{})
 
-		httpmock.RegisterResponder("GET", baseURI+"character?name="+testSearchCharacterQuery,
+		transport.RegisterResponder("GET", defaultBaseURI+"character?name="+testSearchCharacterQuery,
 			func(req *http.Request) (*http.Response, error) {
 				return nil, fmt.Errorf(testErrorText)
 			})
 
-		result, err := g.SearchCharacters(testSearchCharacterQuery)
+		result, err := g.SearchCharacters(context.Background(), CharacterFilter{Name: testSearchCharacterQuery})
 
 		assert.Equal(t, []Character{}, result)
 		assert.Equal(t, "Get \"https://rickandmortyapi.com/api/character?name=Rick\": an error", err.Error())
 	})
 
 	t.Run("it returns an error if decoding the API response returns an error", func(t *testing.T) {
+		t.Parallel()
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		g, err := NewGateway(&GatewayConfig{})
-		if err != nil {
-			t.FailNow()
-		}
+		g, transport := newTestGateway(t, GatewayConfig{})
 
-		httpmock.Activate()
-		defer httpmock.DeactivateAndReset()
-
-		httpmock.RegisterResponder("GET", baseURI+"character?name="+testSearchCharacterQuery,
+		transport.RegisterResponder("GET", defaultBaseURI+"character?name="+testSearchCharacterQuery,
 			func(req *http.Request) (*http.Response, error) {
 				resp, err := httpmock.NewJsonResponse(200, `{"Name": "Foo`+"\u001a"+`"}`)
 				if err != nil {
@@ -284,23 +337,18 @@ func TestGateway_SearchCharacters(t *testing.T) {
 				return resp, nil
 			})
 
-		result, err := g.SearchCharacters(testSearchCharacterQuery)
+		result, err := g.SearchCharacters(context.Background(), CharacterFilter{Name: testSearchCharacterQuery})
 
 		assert.Equal(t, []Character{}, result)
 		assert.Error(t, err)
 	})
 
 	t.Run("it successfully returns a list of characters", func(t *testing.T) {
+		t.Parallel()
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		g, err := NewGateway(&GatewayConfig{})
-		if err != nil {
-			t.FailNow()
-		}
-
-		httpmock.Activate()
-		defer httpmock.DeactivateAndReset()
+		g, transport := newTestGateway(t, GatewayConfig{})
 
 		expectedTime, _ := time.Parse(time.RFC3339, "2017-11-04T18:48:46.250Z")
 		expectedCharacter := Character{
@@ -323,7 +371,7 @@ func TestGateway_SearchCharacters(t *testing.T) {
 			Results: []Character{expectedCharacter},
 		}
 
-		httpmock.RegisterResponder("GET", baseURI+"character?name="+testSearchCharacterQuery,
+		transport.RegisterResponder("GET", defaultBaseURI+"character?name="+testSearchCharacterQuery,
 			func(req *http.Request) (*http.Response, error) {
 				resp, err := httpmock.NewJsonResponse(200, expectedResponse)
 				if err != nil {
@@ -332,50 +380,85 @@ func TestGateway_SearchCharacters(t *testing.T) {
 				return resp, nil
 			})
 
-		result, err := g.SearchCharacters(testSearchCharacterQuery)
+		result, err := g.SearchCharacters(context.Background(), CharacterFilter{Name: testSearchCharacterQuery})
 
 		assert.Equal(t, []Character{expectedCharacter}, result)
 		assert.Nil(t, err)
 	})
+
+	t.Run("it URL-encodes every filter into the query string", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		var rawQuery string
+
+		transport.RegisterResponder("GET", `=~^`+defaultBaseURI+`character\?`,
+			func(req *http.Request) (*http.Response, error) {
+				rawQuery = req.URL.RawQuery
+				return httpmock.NewJsonResponse(200, CharactersListResponse{Info: ApiInfo{Count: 0, Pages: 1}})
+			})
+
+		_, err := g.SearchCharacters(context.Background(), CharacterFilter{
+			Name:    "Mr. Poopybutthole & Rick 2",
+			Status:  "alive",
+			Species: "Poopybutthole",
+			Type:    "Parasite",
+			Gender:  "male",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "gender=male&name=Mr.+Poopybutthole+%26+Rick+2&species=Poopybutthole&status=alive&type=Parasite", rawQuery)
+	})
 }
 
 func TestGateway_ListCharacters(t *testing.T) {
-	t.Run("it returns an error if the API returns an error", func(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns ErrNotFound when the unfiltered listing is missing", func(t *testing.T) {
+		t.Parallel()
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		g, err := NewGateway(&GatewayConfig{})
-		if err != nil {
-			t.FailNow()
-		}
+		g, transport := newTestGateway(t, GatewayConfig{})
 
-		httpmock.Activate()
-		defer httpmock.DeactivateAndReset()
+		transport.RegisterResponder("GET", defaultBaseURI+"character",
+			httpmock.NewStringResponder(404, `{"error":"There is nothing here"}`))
 
-		httpmock.RegisterResponder("GET", baseURI+"character",
+		result, err := g.ListCharacters(context.Background())
+
+		assert.Equal(t, []Character{}, result)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("it returns an error if the API returns an error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character",
 			func(req *http.Request) (*http.Response, error) {
 				return nil, fmt.Errorf(testErrorText)
 			})
 
-		result, err := g.ListCharacters()
+		result, err := g.ListCharacters(context.Background())
 
 		assert.Equal(t, []Character{}, result)
 		assert.Equal(t, "Get \"https://rickandmortyapi.com/api/character\": an error", err.Error())
 	})
 
 	t.Run("it returns an error if decoding the API response returns an error", func(t *testing.T) {
+		t.Parallel()
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		g, err := NewGateway(&GatewayConfig{})
-		if err != nil {
-			t.FailNow()
-		}
-
-		httpmock.Activate()
-		defer httpmock.DeactivateAndReset()
+		g, transport := newTestGateway(t, GatewayConfig{})
 
-		httpmock.RegisterResponder("GET", baseURI+"character",
+		transport.RegisterResponder("GET", defaultBaseURI+"character",
 			func(req *http.Request) (*http.Response, error) {
 				resp, err := httpmock.NewJsonResponse(200, `{"Name": "Foo`+"\u001a"+`"}`)
 				if err != nil {
@@ -384,23 +467,18 @@ func TestGateway_ListCharacters(t *testing.T) {
 				return resp, nil
 			})
 
-		result, err := g.ListCharacters()
+		result, err := g.ListCharacters(context.Background())
 
 		assert.Equal(t, []Character{}, result)
 		assert.Error(t, err)
 	})
 
 	t.Run("it successfully returns a list of characters", func(t *testing.T) {
+		t.Parallel()
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		g, err := NewGateway(&GatewayConfig{})
-		if err != nil {
-			t.FailNow()
-		}
-
-		httpmock.Activate()
-		defer httpmock.DeactivateAndReset()
+		g, transport := newTestGateway(t, GatewayConfig{})
 
 		expectedTime, _ := time.Parse(time.RFC3339, "2017-11-04T18:48:46.250Z")
 		expectedCharacter := Character{
@@ -423,7 +501,7 @@ func TestGateway_ListCharacters(t *testing.T) {
 			Results: []Character{expectedCharacter},
 		}
 
-		httpmock.RegisterResponder("GET", baseURI+"character",
+		transport.RegisterResponder("GET", defaultBaseURI+"character",
 			func(req *http.Request) (*http.Response, error) {
 				resp, err := httpmock.NewJsonResponse(200, expectedResponse)
 				if err != nil {
@@ -432,9 +510,481 @@ func TestGateway_ListCharacters(t *testing.T) {
 				return resp, nil
 			})
 
-		result, err := g.ListCharacters()
+		result, err := g.ListCharacters(context.Background())
 
 		assert.Equal(t, []Character{expectedCharacter}, result)
 		assert.Nil(t, err)
 	})
 }
+
+func TestGateway_Context(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it stops paging when the context is cancelled mid-pagination", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{
+			MaxConcurrentPages: 1,
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		pageResponse := func(page int) CharactersListResponse {
+			return CharactersListResponse{
+				Info: ApiInfo{
+					Count: 3,
+					Pages: 3,
+					Next:  fmt.Sprintf("%scharacter?page=%d", defaultBaseURI, page+1),
+				},
+				Results: []Character{{Id: page}},
+			}
+		}
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, pageResponse(1))
+			})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character?page=2",
+			func(req *http.Request) (*http.Response, error) {
+				cancel()
+				return httpmock.NewJsonResponse(200, pageResponse(2))
+			})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character?page=3",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, pageResponse(3))
+			})
+
+		result, err := g.ListCharacters(ctx)
+
+		assert.Equal(t, []Character{}, result)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 0, transport.GetCallCountInfo()["GET "+defaultBaseURI+"character?page=3"])
+	})
+
+	t.Run("it returns an error when the context deadline has passed", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			httpmock.NewStringResponder(200, `{"id": 1}`))
+
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		result, err := g.GetCharacter(ctx, testCharacterID)
+
+		assert.Equal(t, Character{}, result)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 0, transport.GetTotalCallCount())
+	})
+}
+
+type trackedBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *trackedBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestGateway_UpstreamErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		expectedKind error
+		expectedText string
+	}{
+		{
+			name:         "it returns ErrNotFound when the API responds with a 404",
+			status:       404,
+			body:         `{"error":"Character not found"}`,
+			expectedKind: ErrNotFound,
+			expectedText: "upstream responded with 404: Character not found",
+		},
+		{
+			name:         "it returns ErrBadRequest when the API responds with a 400",
+			status:       400,
+			body:         `{"error":"Hey! you must provide an id"}`,
+			expectedKind: ErrBadRequest,
+			expectedText: "upstream responded with 400: Hey! you must provide an id",
+		},
+		{
+			name:         "it returns ErrRateLimited when the API responds with a 429",
+			status:       429,
+			expectedKind: ErrRateLimited,
+			expectedText: "upstream responded with 429: Too Many Requests",
+		},
+		{
+			name:         "it returns ErrUpstreamUnavailable when the API responds with a 503",
+			status:       503,
+			body:         "<html>down for maintenance</html>",
+			expectedKind: ErrUpstreamUnavailable,
+			expectedText: "upstream responded with 503: Service Unavailable",
+		},
+		{
+			name:         "it returns ErrUpstreamTimeout when the API responds with a 504",
+			status:       504,
+			expectedKind: ErrUpstreamTimeout,
+			expectedText: "upstream responded with 504: Gateway Timeout",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			g, transport := newTestGateway(t, GatewayConfig{})
+
+			transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+				httpmock.NewStringResponder(test.status, test.body))
+
+			result, err := g.GetCharacter(context.Background(), testCharacterID)
+
+			assert.Equal(t, Character{}, result)
+			assert.ErrorIs(t, err, test.expectedKind)
+			assert.EqualError(t, err, test.expectedText)
+
+			var upstreamErr *UpstreamError
+			assert.ErrorAs(t, err, &upstreamErr)
+			assert.Equal(t, test.status, upstreamErr.StatusCode)
+		})
+	}
+
+	t.Run("it returns ErrUpstreamTimeout when the request times out", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			httpmock.NewErrorResponder(context.DeadlineExceeded))
+
+		_, err := g.GetCharacter(context.Background(), testCharacterID)
+
+		assert.ErrorIs(t, err, ErrUpstreamTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("it returns ErrDecode when the API response is malformed", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			httpmock.NewStringResponder(200, `{"id": "one"}`))
+
+		_, err := g.GetCharacter(context.Background(), testCharacterID)
+
+		assert.ErrorIs(t, err, ErrDecode)
+	})
+
+	t.Run("it closes the response body", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		body := &trackedBody{Reader: strings.NewReader(`{"error":"Character not found"}`)}
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			func(req *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: 404, Body: body, Header: http.Header{}}, nil
+			})
+
+		_, err := g.GetCharacter(context.Background(), testCharacterID)
+
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.True(t, body.closed)
+	})
+}
+
+func TestGateway_ConcurrentPages(t *testing.T) {
+	t.Parallel()
+
+	pageResponse := func(page, totalPages int) CharactersListResponse {
+		return CharactersListResponse{
+			Info: ApiInfo{
+				Count: totalPages,
+				Pages: totalPages,
+			},
+			Results: []Character{{Id: page}},
+		}
+	}
+
+	t.Run("it returns an error when an invalid MaxConcurrentPages is passed in", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewGateway(&GatewayConfig{MaxConcurrentPages: -1})
+
+		assert.EqualError(t, fmt.Errorf("invalid MaxConcurrentPages parameter"), err.Error())
+	})
+
+	t.Run("it returns pages in order regardless of completion order", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		const totalPages = 5
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character?name="+testSearchCharacterQuery,
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, pageResponse(1, totalPages))
+			})
+
+		for page := 2; page <= totalPages; page++ {
+			page := page
+
+			transport.RegisterResponder("GET", fmt.Sprintf("%scharacter?name=%s&page=%d", defaultBaseURI, testSearchCharacterQuery, page),
+				func(req *http.Request) (*http.Response, error) {
+					time.Sleep(time.Duration(totalPages-page) * 5 * time.Millisecond)
+					return httpmock.NewJsonResponse(200, pageResponse(page, totalPages))
+				})
+		}
+
+		result, err := g.SearchCharacters(context.Background(), CharacterFilter{Name: testSearchCharacterQuery})
+
+		assert.Equal(t, []Character{{Id: 1}, {Id: 2}, {Id: 3}, {Id: 4}, {Id: 5}}, result)
+		assert.Nil(t, err)
+	})
+
+	t.Run("it never exceeds the configured number of workers", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{
+			MaxConcurrentPages: 2,
+		})
+
+		const totalPages = 8
+
+		var inFlight, maxInFlight int32
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character",
+			func(req *http.Request) (*http.Response, error) {
+				page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+				if page == 0 {
+					return httpmock.NewJsonResponse(200, pageResponse(1, totalPages))
+				}
+
+				current := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+
+				for {
+					observed := atomic.LoadInt32(&maxInFlight)
+					if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+						break
+					}
+				}
+
+				time.Sleep(5 * time.Millisecond)
+
+				return httpmock.NewJsonResponse(200, pageResponse(page, totalPages))
+			})
+
+		result, err := g.ListCharacters(context.Background())
+
+		assert.Len(t, result, totalPages)
+		assert.Nil(t, err)
+		assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+	})
+
+	t.Run("it returns the first page error and cancels the remaining pages", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{
+			MaxConcurrentPages: 1,
+		})
+
+		const totalPages = 4
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, pageResponse(1, totalPages))
+			})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character?page=2",
+			httpmock.NewStringResponder(200, `{"info": "not an object"}`))
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character?page=3",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, pageResponse(3, totalPages))
+			})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character?page=4",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, pageResponse(4, totalPages))
+			})
+
+		result, err := g.ListCharacters(context.Background())
+
+		assert.Equal(t, []Character{}, result)
+		assert.ErrorIs(t, err, ErrDecode)
+		assert.Equal(t, 0, transport.GetCallCountInfo()["GET "+defaultBaseURI+"character?page=4"])
+	})
+}
+
+func TestGateway_CharacterPages(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it yields every page in order", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character?name="+testSearchCharacterQuery,
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, CharactersListResponse{
+					Info:    ApiInfo{Count: 3, Pages: 2, Next: defaultBaseURI + "character?name=" + testSearchCharacterQuery + "&page=2"},
+					Results: []Character{{Id: 1}, {Id: 2}},
+				})
+			})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character?name="+testSearchCharacterQuery+"&page=2",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, CharactersListResponse{
+					Info:    ApiInfo{Count: 3, Pages: 2},
+					Results: []Character{{Id: 3}},
+				})
+			})
+
+		it := g.SearchCharacterPages(context.Background(), CharacterFilter{Name: testSearchCharacterQuery})
+
+		var pages [][]Character
+		for it.Next() {
+			pages = append(pages, it.Page())
+			assert.Equal(t, 3, it.Info().Count)
+		}
+
+		assert.Equal(t, [][]Character{{{Id: 1}, {Id: 2}}, {{Id: 3}}}, pages)
+		assert.Nil(t, it.Err())
+		assert.False(t, it.Next())
+	})
+
+	t.Run("it yields no pages when the filter matches nothing", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character?name=zzz",
+			httpmock.NewStringResponder(404, `{"error":"There is nothing here"}`))
+
+		it := g.SearchCharacterPages(context.Background(), CharacterFilter{Name: "zzz"})
+
+		assert.False(t, it.Next())
+		assert.Nil(t, it.Page())
+		assert.Nil(t, it.Err())
+		assert.Equal(t, 1, transport.GetTotalCallCount())
+	})
+
+	t.Run("it reports ErrNotFound when the unfiltered listing is missing", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character",
+			httpmock.NewStringResponder(404, `{"error":"There is nothing here"}`))
+
+		it := g.ListCharacterPages(context.Background())
+
+		assert.False(t, it.Next())
+		assert.ErrorIs(t, it.Err(), ErrNotFound)
+	})
+
+	t.Run("it stays on the configured BaseURL instead of following the upstream's next link", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		const baseURL = "http://proxy.test/api/"
+
+		g, transport := newTestGateway(t, GatewayConfig{
+			BaseURL: baseURL,
+		})
+
+		transport.RegisterResponder("GET", baseURL+"character",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, CharactersListResponse{
+					Info:    ApiInfo{Count: 2, Pages: 2, Next: defaultBaseURI + "character?page=2"},
+					Results: []Character{{Id: 1}},
+				})
+			})
+
+		transport.RegisterResponder("GET", baseURL+"character?page=2",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, CharactersListResponse{
+					Info:    ApiInfo{Count: 2, Pages: 2},
+					Results: []Character{{Id: 2}},
+				})
+			})
+
+		it := g.ListCharacterPages(context.Background())
+
+		var pages [][]Character
+		for it.Next() {
+			pages = append(pages, it.Page())
+		}
+
+		assert.Equal(t, [][]Character{{{Id: 1}}, {{Id: 2}}}, pages)
+		assert.Nil(t, it.Err())
+		assert.Equal(t, 2, transport.GetTotalCallCount())
+	})
+
+	t.Run("it stops and reports the error when a page fails", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, CharactersListResponse{
+					Info:    ApiInfo{Count: 2, Pages: 2, Next: defaultBaseURI + "character?page=2"},
+					Results: []Character{{Id: 1}},
+				})
+			})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character?page=2",
+			httpmock.NewStringResponder(500, ""))
+
+		it := g.ListCharacterPages(context.Background())
+
+		assert.True(t, it.Next())
+		assert.Equal(t, []Character{{Id: 1}}, it.Page())
+		assert.False(t, it.Next())
+		assert.Nil(t, it.Page())
+		assert.ErrorIs(t, it.Err(), ErrUpstreamUnavailable)
+	})
+}
@@ -0,0 +1,80 @@
+package rick_and_morty
+
+import "context"
+
+type pageIterator struct {
+	ctx          context.Context
+	gateway      *gateway
+	firstPageURL string
+	nextURL      string
+	pageNumber   int
+	page         []Character
+	info         ApiInfo
+	err          error
+}
+
+func (g *gateway) ListCharacterPages(ctx context.Context) CharacterIterator {
+	return g.newPageIterator(ctx, g.baseURI+"character")
+}
+
+func (g *gateway) SearchCharacterPages(ctx context.Context, filter CharacterFilter) CharacterIterator {
+	return g.newPageIterator(ctx, g.baseURI+"character?"+filter.query().Encode())
+}
+
+func (g *gateway) newPageIterator(ctx context.Context, firstPageURL string) *pageIterator {
+	return &pageIterator{
+		ctx:          ctx,
+		gateway:      g,
+		firstPageURL: firstPageURL,
+		nextURL:      firstPageURL,
+	}
+}
+
+// Next fetches the page following the current one, returning false once the
+// listing is exhausted or a request fails. A filtered listing that matches
+// nothing simply yields no pages.
+func (it *pageIterator) Next() bool {
+	if it.err != nil || it.nextURL == "" {
+		it.page = nil
+		return false
+	}
+
+	apiData := CharactersListResponse{}
+
+	err := it.gateway.getJSON(it.ctx, it.nextURL, &apiData)
+	if it.pageNumber == 0 && emptyListing(it.firstPageURL, err) {
+		it.nextURL = ""
+		it.page = nil
+		return false
+	}
+	if err != nil {
+		it.err = err
+		it.page = nil
+		return false
+	}
+
+	it.page = apiData.Results
+	it.info = apiData.Info
+	it.pageNumber++
+
+	// The upstream's next link is absolute and would bypass a configured
+	// BaseURL, so the following page is derived from the page count instead.
+	it.nextURL = ""
+	if it.pageNumber < apiData.Info.Pages {
+		it.nextURL = pageURL(it.firstPageURL, it.pageNumber+1)
+	}
+
+	return true
+}
+
+func (it *pageIterator) Page() []Character {
+	return it.page
+}
+
+func (it *pageIterator) Info() ApiInfo {
+	return it.info
+}
+
+func (it *pageIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,63 @@
+package rick_and_morty
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+func (g *gateway) GetLocation(ctx context.Context, id int) (Location, error) {
+	apiData := Location{}
+
+	err := g.getJSON(ctx, g.baseURI+"location/"+strconv.Itoa(id), &apiData)
+	if err != nil {
+		return Location{}, err
+	}
+
+	return apiData, nil
+}
+
+func (g *gateway) GetLocations(ctx context.Context, ids []int) ([]Location, error) {
+	locationList, _, err := getMany(ctx, g, "location", ids, func(l Location) int { return l.Id })
+	if err != nil {
+		return []Location{}, err
+	}
+
+	return locationList, nil
+}
+
+func (g *gateway) SearchLocations(ctx context.Context, filter LocationFilter) ([]Location, error) {
+	locationList, err := getAllData[Location](ctx, g, g.baseURI+"location?"+filter.query().Encode())
+	if err != nil {
+		return []Location{}, err
+	}
+
+	return locationList, nil
+}
+
+func (g *gateway) ListLocations(ctx context.Context) ([]Location, error) {
+	locationList, err := getAllData[Location](ctx, g, g.baseURI+"location")
+	if err != nil {
+		return []Location{}, err
+	}
+
+	return locationList, nil
+}
+
+func (f LocationFilter) query() url.Values {
+	query := url.Values{}
+
+	if f.Name != "" {
+		query.Set("name", f.Name)
+	}
+
+	if f.Type != "" {
+		query.Set("type", f.Type)
+	}
+
+	if f.Dimension != "" {
+		query.Set("dimension", f.Dimension)
+	}
+
+	return query
+}
@@ -0,0 +1,257 @@
+package rick_and_morty
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testLocationID              = 1
+	testMultipleLocationIDsPath = "1,3"
+)
+
+var testMultipleLocationIDs = []int{1, 3}
+
+func testLocations() []Location {
+	earthTime, _ := time.Parse(time.RFC3339, "2017-11-10T12:42:04.162Z")
+	citadelTime, _ := time.Parse(time.RFC3339, "2017-11-10T13:08:13.191Z")
+
+	return []Location{
+		{
+			Id:        1,
+			Name:      "Earth (C-137)",
+			Type:      "Planet",
+			Dimension: "Dimension C-137",
+			Residents: []string{"https://rickandmortyapi.com/api/character/38"},
+			Url:       "https://rickandmortyapi.com/api/location/1",
+			Created:   earthTime,
+		},
+		{
+			Id:        3,
+			Name:      "Citadel of Ricks",
+			Type:      "Space station",
+			Dimension: "unknown",
+			Residents: []string{"https://rickandmortyapi.com/api/character/8"},
+			Url:       "https://rickandmortyapi.com/api/location/3",
+			Created:   citadelTime,
+		},
+	}
+}
+
+func TestGateway_GetLocation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns ErrNotFound if the location does not exist", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"location/"+strconv.Itoa(testLocationID),
+			httpmock.NewStringResponder(404, `{"error":"Location not found"}`))
+
+		result, err := g.GetLocation(context.Background(), testLocationID)
+
+		assert.Equal(t, Location{}, result)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("it successfully returns a Location", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		expectedLocation := testLocations()[0]
+
+		transport.RegisterResponder("GET", defaultBaseURI+"location/"+strconv.Itoa(testLocationID),
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, expectedLocation)
+			})
+
+		result, err := g.GetLocation(context.Background(), testLocationID)
+
+		assert.Equal(t, expectedLocation, result)
+		assert.Nil(t, err)
+	})
+}
+
+func TestGateway_GetLocations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns an error if decoding the API response returns an error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"location/"+testMultipleLocationIDsPath,
+			httpmock.NewStringResponder(200, `{"id": "one"}`))
+
+		result, err := g.GetLocations(context.Background(), testMultipleLocationIDs)
+
+		assert.Equal(t, []Location{}, result)
+		assert.ErrorIs(t, err, ErrDecode)
+	})
+
+	t.Run("it successfully returns a list of locations", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		expectedLocations := testLocations()
+
+		transport.RegisterResponder("GET", defaultBaseURI+"location/"+testMultipleLocationIDsPath,
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, expectedLocations)
+			})
+
+		result, err := g.GetLocations(context.Background(), testMultipleLocationIDs)
+
+		assert.Equal(t, expectedLocations, result)
+		assert.Nil(t, err)
+	})
+}
+
+func TestGateway_SearchLocations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns an empty list when the filter matches nothing", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"location?name=zzz",
+			httpmock.NewStringResponder(404, `{"error":"There is nothing here"}`))
+
+		result, err := g.SearchLocations(context.Background(), LocationFilter{Name: "zzz"})
+
+		assert.Equal(t, []Location{}, result)
+		assert.Nil(t, err)
+	})
+
+	t.Run("it returns an error if the API returns an error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"location?type=Moon",
+			httpmock.NewStringResponder(500, ""))
+
+		result, err := g.SearchLocations(context.Background(), LocationFilter{Type: "Moon"})
+
+		assert.Equal(t, []Location{}, result)
+		assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+	})
+
+	t.Run("it encodes every filter into the query string", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		expectedLocation := testLocations()[0]
+
+		transport.RegisterResponder("GET", defaultBaseURI+"location?dimension=Dimension+C-137&name=Earth&type=Planet",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, listResponse[Location]{
+					Info:    ApiInfo{Count: 1, Pages: 1},
+					Results: []Location{expectedLocation},
+				})
+			})
+
+		result, err := g.SearchLocations(context.Background(), LocationFilter{
+			Name:      "Earth",
+			Type:      "Planet",
+			Dimension: "Dimension C-137",
+		})
+
+		assert.Equal(t, []Location{expectedLocation}, result)
+		assert.Nil(t, err)
+	})
+}
+
+func TestGateway_ListLocations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns ErrNotFound when the unfiltered listing is missing", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"location",
+			httpmock.NewStringResponder(404, `{"error":"There is nothing here"}`))
+
+		result, err := g.ListLocations(context.Background())
+
+		assert.Equal(t, []Location{}, result)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("it returns an error if the API returns an error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"location",
+			httpmock.NewErrorResponder(fmt.Errorf(testErrorText)))
+
+		result, err := g.ListLocations(context.Background())
+
+		assert.Equal(t, []Location{}, result)
+		assert.Equal(t, "Get \"https://rickandmortyapi.com/api/location\": an error", err.Error())
+	})
+
+	t.Run("it successfully returns every page of locations", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		expectedLocations := testLocations()
+
+		transport.RegisterResponder("GET", defaultBaseURI+"location",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, listResponse[Location]{
+					Info:    ApiInfo{Count: 2, Pages: 2},
+					Results: expectedLocations[:1],
+				})
+			})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"location?page=2",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, listResponse[Location]{
+					Info:    ApiInfo{Count: 2, Pages: 2},
+					Results: expectedLocations[1:],
+				})
+			})
+
+		result, err := g.ListLocations(context.Background())
+
+		assert.Equal(t, expectedLocations, result)
+		assert.Nil(t, err)
+	})
+}
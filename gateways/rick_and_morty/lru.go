@@ -0,0 +1,109 @@
+package rick_and_morty
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lruCache is a size-bounded, least-recently-used map whose entries also
+// expire after a per-entry TTL. It is safe for concurrent use.
+type lruCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // Front is most recently used.
+	entries    map[string]*list.Element
+	now        func() time.Time
+	onEvict    func()
+}
+
+type lruEntry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+func newLRUCache(maxEntries int, now func() time.Time, onEvict func()) *lruCache {
+	return &lruCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		now:        now,
+		onEvict:    onEvict,
+	}
+}
+
+// get returns the value stored for key and whether it is still fresh.
+// Expired entries are kept until they are replaced or evicted, so that the
+// caller can revalidate them.
+func (c *lruCache) get(key string) (value interface{}, fresh bool, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false, false
+	}
+
+	c.order.MoveToFront(element)
+
+	entry := element.Value.(*lruEntry)
+
+	return entry.value, c.now().Before(entry.expiresAt), true
+}
+
+func (c *lruCache) set(key string, value interface{}, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(ttl)
+
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+
+	for c.order.Len() > c.maxEntries {
+		c.removeElement(c.order.Back())
+		if c.onEvict != nil {
+			c.onEvict()
+		}
+	}
+}
+
+// removeMatching drops every entry whose key satisfies match.
+func (c *lruCache) removeMatching(match func(key string) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, element := range c.entries {
+		if match(key) {
+			c.removeElement(element)
+		}
+	}
+}
+
+func (c *lruCache) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+}
+
+func (c *lruCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *lruCache) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*lruEntry).key)
+}
@@ -5,6 +5,7 @@
 package mock_rick_and_morty
 
 import (
+	context "context"
 	rick_and_morty "gojo/gateways/rick_and_morty"
 	reflect "reflect"
 
@@ -34,62 +35,317 @@ func (m *MockGateway) EXPECT() *MockGatewayMockRecorder {
 	return m.recorder
 }
 
+// CircuitState mocks base method.
+func (m *MockGateway) CircuitState() rick_and_morty.CircuitState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CircuitState")
+	ret0, _ := ret[0].(rick_and_morty.CircuitState)
+	return ret0
+}
+
+// CircuitState indicates an expected call of CircuitState.
+func (mr *MockGatewayMockRecorder) CircuitState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CircuitState", reflect.TypeOf((*MockGateway)(nil).CircuitState))
+}
+
+// Close mocks base method.
+func (m *MockGateway) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockGatewayMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockGateway)(nil).Close))
+}
+
 // GetCharacter mocks base method.
-func (m *MockGateway) GetCharacter(id string) (rick_and_morty.Character, error) {
+func (m *MockGateway) GetCharacter(ctx context.Context, id int) (rick_and_morty.Character, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCharacter", id)
+	ret := m.ctrl.Call(m, "GetCharacter", ctx, id)
 	ret0, _ := ret[0].(rick_and_morty.Character)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCharacter indicates an expected call of GetCharacter.
-func (mr *MockGatewayMockRecorder) GetCharacter(id interface{}) *gomock.Call {
+func (mr *MockGatewayMockRecorder) GetCharacter(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCharacter", reflect.TypeOf((*MockGateway)(nil).GetCharacter), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCharacter", reflect.TypeOf((*MockGateway)(nil).GetCharacter), ctx, id)
 }
 
 // GetCharacters mocks base method.
-func (m *MockGateway) GetCharacters(ids string) ([]rick_and_morty.Character, error) {
+func (m *MockGateway) GetCharacters(ctx context.Context, ids []int) (rick_and_morty.CharacterBatch, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCharacters", ids)
-	ret0, _ := ret[0].([]rick_and_morty.Character)
+	ret := m.ctrl.Call(m, "GetCharacters", ctx, ids)
+	ret0, _ := ret[0].(rick_and_morty.CharacterBatch)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCharacters indicates an expected call of GetCharacters.
-func (mr *MockGatewayMockRecorder) GetCharacters(ids interface{}) *gomock.Call {
+func (mr *MockGatewayMockRecorder) GetCharacters(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCharacters", reflect.TypeOf((*MockGateway)(nil).GetCharacters), ctx, ids)
+}
+
+// GetEpisode mocks base method.
+func (m *MockGateway) GetEpisode(ctx context.Context, id int) (rick_and_morty.Episode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEpisode", ctx, id)
+	ret0, _ := ret[0].(rick_and_morty.Episode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEpisode indicates an expected call of GetEpisode.
+func (mr *MockGatewayMockRecorder) GetEpisode(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEpisode", reflect.TypeOf((*MockGateway)(nil).GetEpisode), ctx, id)
+}
+
+// GetEpisodes mocks base method.
+func (m *MockGateway) GetEpisodes(ctx context.Context, ids []int) ([]rick_and_morty.Episode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEpisodes", ctx, ids)
+	ret0, _ := ret[0].([]rick_and_morty.Episode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEpisodes indicates an expected call of GetEpisodes.
+func (mr *MockGatewayMockRecorder) GetEpisodes(ctx, ids interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCharacters", reflect.TypeOf((*MockGateway)(nil).GetCharacters), ids)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEpisodes", reflect.TypeOf((*MockGateway)(nil).GetEpisodes), ctx, ids)
+}
+
+// GetLocation mocks base method.
+func (m *MockGateway) GetLocation(ctx context.Context, id int) (rick_and_morty.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocation", ctx, id)
+	ret0, _ := ret[0].(rick_and_morty.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocation indicates an expected call of GetLocation.
+func (mr *MockGatewayMockRecorder) GetLocation(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocation", reflect.TypeOf((*MockGateway)(nil).GetLocation), ctx, id)
+}
+
+// GetLocations mocks base method.
+func (m *MockGateway) GetLocations(ctx context.Context, ids []int) ([]rick_and_morty.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocations", ctx, ids)
+	ret0, _ := ret[0].([]rick_and_morty.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocations indicates an expected call of GetLocations.
+func (mr *MockGatewayMockRecorder) GetLocations(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocations", reflect.TypeOf((*MockGateway)(nil).GetLocations), ctx, ids)
+}
+
+// ListCharacterPages mocks base method.
+func (m *MockGateway) ListCharacterPages(ctx context.Context) rick_and_morty.CharacterIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCharacterPages", ctx)
+	ret0, _ := ret[0].(rick_and_morty.CharacterIterator)
+	return ret0
+}
+
+// ListCharacterPages indicates an expected call of ListCharacterPages.
+func (mr *MockGatewayMockRecorder) ListCharacterPages(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCharacterPages", reflect.TypeOf((*MockGateway)(nil).ListCharacterPages), ctx)
 }
 
 // ListCharacters mocks base method.
-func (m *MockGateway) ListCharacters() ([]rick_and_morty.Character, error) {
+func (m *MockGateway) ListCharacters(ctx context.Context) ([]rick_and_morty.Character, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListCharacters")
+	ret := m.ctrl.Call(m, "ListCharacters", ctx)
 	ret0, _ := ret[0].([]rick_and_morty.Character)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListCharacters indicates an expected call of ListCharacters.
-func (mr *MockGatewayMockRecorder) ListCharacters() *gomock.Call {
+func (mr *MockGatewayMockRecorder) ListCharacters(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCharacters", reflect.TypeOf((*MockGateway)(nil).ListCharacters), ctx)
+}
+
+// ListEpisodes mocks base method.
+func (m *MockGateway) ListEpisodes(ctx context.Context) ([]rick_and_morty.Episode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEpisodes", ctx)
+	ret0, _ := ret[0].([]rick_and_morty.Episode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEpisodes indicates an expected call of ListEpisodes.
+func (mr *MockGatewayMockRecorder) ListEpisodes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEpisodes", reflect.TypeOf((*MockGateway)(nil).ListEpisodes), ctx)
+}
+
+// ListLocations mocks base method.
+func (m *MockGateway) ListLocations(ctx context.Context) ([]rick_and_morty.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLocations", ctx)
+	ret0, _ := ret[0].([]rick_and_morty.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLocations indicates an expected call of ListLocations.
+func (mr *MockGatewayMockRecorder) ListLocations(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLocations", reflect.TypeOf((*MockGateway)(nil).ListLocations), ctx)
+}
+
+// SearchCharacterPages mocks base method.
+func (m *MockGateway) SearchCharacterPages(ctx context.Context, filter rick_and_morty.CharacterFilter) rick_and_morty.CharacterIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchCharacterPages", ctx, filter)
+	ret0, _ := ret[0].(rick_and_morty.CharacterIterator)
+	return ret0
+}
+
+// SearchCharacterPages indicates an expected call of SearchCharacterPages.
+func (mr *MockGatewayMockRecorder) SearchCharacterPages(ctx, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCharacters", reflect.TypeOf((*MockGateway)(nil).ListCharacters))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCharacterPages", reflect.TypeOf((*MockGateway)(nil).SearchCharacterPages), ctx, filter)
 }
 
 // SearchCharacters mocks base method.
-func (m *MockGateway) SearchCharacters(name string) ([]rick_and_morty.Character, error) {
+func (m *MockGateway) SearchCharacters(ctx context.Context, filter rick_and_morty.CharacterFilter) ([]rick_and_morty.Character, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchCharacters", name)
+	ret := m.ctrl.Call(m, "SearchCharacters", ctx, filter)
 	ret0, _ := ret[0].([]rick_and_morty.Character)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SearchCharacters indicates an expected call of SearchCharacters.
-func (mr *MockGatewayMockRecorder) SearchCharacters(name interface{}) *gomock.Call {
+func (mr *MockGatewayMockRecorder) SearchCharacters(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCharacters", reflect.TypeOf((*MockGateway)(nil).SearchCharacters), ctx, filter)
+}
+
+// SearchEpisodes mocks base method.
+func (m *MockGateway) SearchEpisodes(ctx context.Context, filter rick_and_morty.EpisodeFilter) ([]rick_and_morty.Episode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchEpisodes", ctx, filter)
+	ret0, _ := ret[0].([]rick_and_morty.Episode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchEpisodes indicates an expected call of SearchEpisodes.
+func (mr *MockGatewayMockRecorder) SearchEpisodes(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchEpisodes", reflect.TypeOf((*MockGateway)(nil).SearchEpisodes), ctx, filter)
+}
+
+// SearchLocations mocks base method.
+func (m *MockGateway) SearchLocations(ctx context.Context, filter rick_and_morty.LocationFilter) ([]rick_and_morty.Location, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchLocations", ctx, filter)
+	ret0, _ := ret[0].([]rick_and_morty.Location)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchLocations indicates an expected call of SearchLocations.
+func (mr *MockGatewayMockRecorder) SearchLocations(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchLocations", reflect.TypeOf((*MockGateway)(nil).SearchLocations), ctx, filter)
+}
+
+// MockCharacterIterator is a mock of CharacterIterator interface.
+type MockCharacterIterator struct {
+	ctrl     *gomock.Controller
+	recorder *MockCharacterIteratorMockRecorder
+}
+
+// MockCharacterIteratorMockRecorder is the mock recorder for MockCharacterIterator.
+type MockCharacterIteratorMockRecorder struct {
+	mock *MockCharacterIterator
+}
+
+// NewMockCharacterIterator creates a new mock instance.
+func NewMockCharacterIterator(ctrl *gomock.Controller) *MockCharacterIterator {
+	mock := &MockCharacterIterator{ctrl: ctrl}
+	mock.recorder = &MockCharacterIteratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCharacterIterator) EXPECT() *MockCharacterIteratorMockRecorder {
+	return m.recorder
+}
+
+// Err mocks base method.
+func (m *MockCharacterIterator) Err() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Err")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Err indicates an expected call of Err.
+func (mr *MockCharacterIteratorMockRecorder) Err() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Err", reflect.TypeOf((*MockCharacterIterator)(nil).Err))
+}
+
+// Info mocks base method.
+func (m *MockCharacterIterator) Info() rick_and_morty.ApiInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Info")
+	ret0, _ := ret[0].(rick_and_morty.ApiInfo)
+	return ret0
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockCharacterIteratorMockRecorder) Info() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockCharacterIterator)(nil).Info))
+}
+
+// Next mocks base method.
+func (m *MockCharacterIterator) Next() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Next")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Next indicates an expected call of Next.
+func (mr *MockCharacterIteratorMockRecorder) Next() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockCharacterIterator)(nil).Next))
+}
+
+// Page mocks base method.
+func (m *MockCharacterIterator) Page() []rick_and_morty.Character {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Page")
+	ret0, _ := ret[0].([]rick_and_morty.Character)
+	return ret0
+}
+
+// Page indicates an expected call of Page.
+func (mr *MockCharacterIteratorMockRecorder) Page() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCharacters", reflect.TypeOf((*MockGateway)(nil).SearchCharacters), name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Page", reflect.TypeOf((*MockCharacterIterator)(nil).Page))
 }
@@ -0,0 +1,174 @@
+package rick_and_morty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+var errRateLimitExceeded = errors.New("rate limited: upstream request budget exhausted")
+
+// RateLimitConfig configures a token bucket shared by every upstream request
+// the gateway makes, pages included. Requests wait for a token, or fail
+// straight away with ErrRateLimited when FailFast is set or when waiting
+// would outlast the request's context. Each upstream 429 halves the rate,
+// down to MinRequestsPerSecond, and pauses requests for any Retry-After;
+// successful requests then gradually restore the configured rate. A zero
+// RequestsPerSecond disables the limiter.
+type RateLimitConfig struct {
+	RequestsPerSecond    float64
+	Burst                int     // Defaults to 1.
+	MinRequestsPerSecond float64 // Defaults to a tenth of RequestsPerSecond.
+	FailFast             bool
+}
+
+type rateLimiter struct {
+	limiter  *rate.Limiter
+	maxLimit rate.Limit
+	minLimit rate.Limit
+	failFast bool
+	now      func() time.Time
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+// newRateLimiter returns nil when rate limiting is disabled; a nil
+// *rateLimiter never delays a request.
+func newRateLimiter(cfg RateLimitConfig) (*rateLimiter, error) {
+	switch {
+	case cfg.RequestsPerSecond < 0:
+		return nil, fmt.Errorf("invalid RateLimit.RequestsPerSecond parameter")
+	case cfg.Burst < 0:
+		return nil, fmt.Errorf("invalid RateLimit.Burst parameter")
+	case cfg.MinRequestsPerSecond < 0 || cfg.MinRequestsPerSecond > cfg.RequestsPerSecond:
+		return nil, fmt.Errorf("invalid RateLimit.MinRequestsPerSecond parameter")
+	case cfg.RequestsPerSecond == 0:
+		return nil, nil
+	}
+
+	burst := cfg.Burst
+	if burst == 0 {
+		burst = 1
+	}
+
+	minRequestsPerSecond := cfg.MinRequestsPerSecond
+	if minRequestsPerSecond == 0 {
+		minRequestsPerSecond = cfg.RequestsPerSecond / 10
+	}
+
+	return &rateLimiter{
+		limiter:  rate.NewLimiter(rate.Limit(cfg.RequestsPerSecond), burst),
+		maxLimit: rate.Limit(cfg.RequestsPerSecond),
+		minLimit: rate.Limit(minRequestsPerSecond),
+		failFast: cfg.FailFast,
+		now:      time.Now,
+	}, nil
+}
+
+// call waits for a token before running request, then adjusts the rate to
+// the upstream's response.
+func (l *rateLimiter) call(ctx context.Context, request func() error) error {
+	if l == nil {
+		return request()
+	}
+
+	if err := l.wait(ctx); err != nil {
+		return err
+	}
+
+	err := request()
+
+	if errors.Is(err, ErrRateLimited) {
+		var upstreamErr *UpstreamError
+		errors.As(err, &upstreamErr)
+		l.slowDown(upstreamErr.RetryAfter)
+	} else if err == nil {
+		l.speedUp()
+	}
+
+	return err
+}
+
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if pause := l.pauseRemaining(); pause > 0 {
+		if l.failFast || exceedsDeadline(ctx, pause) {
+			return rateLimitedError(pause)
+		}
+
+		if err := sleepContext(ctx, pause); err != nil {
+			return transportError(err)
+		}
+	}
+
+	if l.failFast {
+		reservation := l.limiter.Reserve()
+		if delay := reservation.Delay(); delay > 0 {
+			reservation.Cancel()
+			return rateLimitedError(delay)
+		}
+		return nil
+	}
+
+	if err := l.limiter.Wait(ctx); err != nil {
+		if ctx.Err() != nil {
+			return transportError(ctx.Err())
+		}
+		return rateLimitedError(0)
+	}
+
+	return nil
+}
+
+// slowDown halves the request rate and pauses all requests for retryAfter.
+func (l *rateLimiter) slowDown(retryAfter time.Duration) {
+	limit := l.limiter.Limit() / 2
+	if limit < l.minLimit {
+		limit = l.minLimit
+	}
+	l.limiter.SetLimit(limit)
+
+	if retryAfter > 0 {
+		l.mu.Lock()
+		if pausedUntil := l.now().Add(retryAfter); pausedUntil.After(l.pausedUntil) {
+			l.pausedUntil = pausedUntil
+		}
+		l.mu.Unlock()
+	}
+}
+
+// speedUp restores a twentieth of the configured rate after a success.
+func (l *rateLimiter) speedUp() {
+	limit := l.limiter.Limit()
+	if limit >= l.maxLimit {
+		return
+	}
+
+	limit = rate.Limit(math.Min(float64(limit+l.maxLimit/20), float64(l.maxLimit)))
+	l.limiter.SetLimit(limit)
+}
+
+func (l *rateLimiter) pauseRemaining() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.pausedUntil.Sub(l.now())
+}
+
+func exceedsDeadline(ctx context.Context, d time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && time.Until(deadline) < d
+}
+
+func rateLimitedError(retryAfter time.Duration) error {
+	return &UpstreamError{
+		Kind:       ErrRateLimited,
+		RetryAfter: retryAfter,
+		Err:        errRateLimitExceeded,
+	}
+}
@@ -0,0 +1,191 @@
+package rick_and_morty
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestGateway_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns an error when an invalid config is passed in", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewGateway(&GatewayConfig{RateLimit: RateLimitConfig{RequestsPerSecond: 1, MinRequestsPerSecond: 2}})
+
+		assert.EqualError(t, fmt.Errorf("invalid RateLimit.MinRequestsPerSecond parameter"), err.Error())
+	})
+
+	t.Run("it spaces requests out to the configured rate", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{RateLimit: RateLimitConfig{RequestsPerSecond: 20}})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			httpmock.NewStringResponder(200, `{"id": 1}`))
+
+		start := time.Now()
+
+		for i := 0; i < 3; i++ {
+			_, err := g.GetCharacter(context.Background(), testCharacterID)
+
+			assert.Nil(t, err)
+		}
+
+		assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	})
+
+	t.Run("it shares one budget across methods and listing pages", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{RateLimit: RateLimitConfig{RequestsPerSecond: 1, Burst: 3, FailFast: true}})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, CharactersListResponse{
+					Info:    ApiInfo{Count: 3, Pages: 3},
+					Results: []Character{{Id: 1}},
+				})
+			})
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			httpmock.NewStringResponder(200, `{"id": 1}`))
+
+		_, err := g.ListCharacters(context.Background())
+		assert.Nil(t, err)
+
+		_, err = g.GetCharacter(context.Background(), testCharacterID)
+
+		var upstreamErr *UpstreamError
+
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.ErrorAs(t, err, &upstreamErr)
+		assert.Greater(t, upstreamErr.RetryAfter, time.Duration(0))
+		assert.Equal(t, 3, transport.GetTotalCallCount())
+	})
+
+	t.Run("it fails instead of waiting past the request deadline", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{RateLimit: RateLimitConfig{RequestsPerSecond: 0.1}})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			httpmock.NewStringResponder(200, `{"id": 1}`))
+
+		_, err := g.GetCharacter(context.Background(), testCharacterID)
+		assert.Nil(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+
+		_, err = g.GetCharacter(ctx, testCharacterID)
+
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.Less(t, time.Since(start), 50*time.Millisecond)
+		assert.Equal(t, 1, transport.GetTotalCallCount())
+	})
+
+	t.Run("it stops waiting when the context is cancelled", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{RateLimit: RateLimitConfig{RequestsPerSecond: 0.1}})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			httpmock.NewStringResponder(200, `{"id": 1}`))
+
+		_, err := g.GetCharacter(context.Background(), testCharacterID)
+		assert.Nil(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			_, err = g.GetCharacter(ctx, testCharacterID)
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+		wg.Wait()
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, transport.GetTotalCallCount())
+	})
+
+	t.Run("it slows down after a 429 and recovers after successes", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{RateLimit: RateLimitConfig{RequestsPerSecond: 1000, Burst: 100}})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/1",
+			httpmock.NewStringResponder(429, ""))
+		transport.RegisterResponder("GET", defaultBaseURI+"character/2",
+			httpmock.NewStringResponder(200, `{"id": 2}`))
+
+		_, err := g.GetCharacter(context.Background(), 1)
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.Equal(t, rate.Limit(500), g.limiter.limiter.Limit())
+
+		_, err = g.GetCharacter(context.Background(), 1)
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.Equal(t, rate.Limit(250), g.limiter.limiter.Limit())
+
+		for i := 0; i < 20; i++ {
+			_, err = g.GetCharacter(context.Background(), 2)
+			assert.Nil(t, err)
+		}
+
+		assert.Equal(t, rate.Limit(1000), g.limiter.limiter.Limit())
+	})
+
+	t.Run("it pauses every request for the upstream's Retry-After", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{RateLimit: RateLimitConfig{RequestsPerSecond: 1000, Burst: 100, FailFast: true}})
+
+		rateLimited := httpmock.NewStringResponse(429, "")
+		rateLimited.Header.Set("Retry-After", "30")
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/1",
+			httpmock.ResponderFromResponse(rateLimited))
+		transport.RegisterResponder("GET", defaultBaseURI+"character/2",
+			httpmock.NewStringResponder(200, `{"id": 2}`))
+
+		_, err := g.GetCharacter(context.Background(), 1)
+		assert.ErrorIs(t, err, ErrRateLimited)
+
+		_, err = g.GetCharacter(context.Background(), 2)
+
+		var upstreamErr *UpstreamError
+
+		assert.ErrorAs(t, err, &upstreamErr)
+		assert.Equal(t, 0, upstreamErr.StatusCode)
+		assert.InDelta(t, 30*time.Second, upstreamErr.RetryAfter, float64(time.Second))
+		assert.Equal(t, 1, transport.GetTotalCallCount())
+	})
+}
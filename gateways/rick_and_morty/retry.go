@@ -0,0 +1,169 @@
+package rick_and_morty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 100 * time.Millisecond
+	defaultRetryMaxDelay  = 5 * time.Second
+)
+
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy controls how a failed upstream request is retried. Transport
+// failures and the RetryableStatusCodes are retried with exponential backoff
+// from BaseDelay up to MaxDelay; Jitter (0 to 1) randomly shortens each delay
+// by up to that fraction. A Retry-After header overrides the backoff, and a
+// Retry-After longer than MaxDelay ends the retries early.
+type RetryPolicy struct {
+	MaxAttempts          int // Total attempts, including the first; 0 or 1 disables retries.
+	BaseDelay            time.Duration
+	MaxDelay             time.Duration
+	Jitter               float64
+	RetryableStatusCodes []int // Defaults to 429, 502, 503 and 504.
+}
+
+type retrier struct {
+	policy    RetryPolicy
+	retryable map[int]bool
+	sleep     func(ctx context.Context, d time.Duration) error
+
+	randMu sync.Mutex
+	rand   *rand.Rand
+}
+
+func newRetrier(policy RetryPolicy) (*retrier, error) {
+	switch {
+	case policy.MaxAttempts < 0:
+		return nil, fmt.Errorf("invalid Retry.MaxAttempts parameter")
+	case policy.BaseDelay < 0:
+		return nil, fmt.Errorf("invalid Retry.BaseDelay parameter")
+	case policy.MaxDelay < 0:
+		return nil, fmt.Errorf("invalid Retry.MaxDelay parameter")
+	case policy.Jitter < 0 || policy.Jitter > 1:
+		return nil, fmt.Errorf("invalid Retry.Jitter parameter")
+	}
+
+	if policy.BaseDelay == 0 {
+		policy.BaseDelay = defaultRetryBaseDelay
+	}
+
+	if policy.MaxDelay == 0 {
+		policy.MaxDelay = defaultRetryMaxDelay
+	}
+
+	if policy.RetryableStatusCodes == nil {
+		policy.RetryableStatusCodes = defaultRetryableStatusCodes
+	}
+
+	retryable := make(map[int]bool, len(policy.RetryableStatusCodes))
+	for _, statusCode := range policy.RetryableStatusCodes {
+		retryable[statusCode] = true
+	}
+
+	return &retrier{
+		policy:    policy,
+		retryable: retryable,
+		sleep:     sleepContext,
+		rand:      rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+// do calls attempt until it succeeds, fails with a non-retryable error, or
+// the policy runs out of attempts.
+func (r *retrier) do(ctx context.Context, attempt func() error) error {
+	for n := 1; ; n++ {
+		err := attempt()
+		if err == nil || n >= r.policy.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+
+		delay, ok := r.delay(n, err)
+		if !ok {
+			return err
+		}
+
+		if sleepErr := r.sleep(ctx, delay); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+// delay reports how long to wait before retrying after the given failed
+// attempt, or false if err should not be retried.
+func (r *retrier) delay(attempt int, err error) (time.Duration, bool) {
+	var upstreamErr *UpstreamError
+	if !errors.As(err, &upstreamErr) {
+		return 0, false
+	}
+
+	// Without a status code, only transport failures are worth retrying; an
+	// open circuit, an exhausted rate limit or a bad body will not improve.
+	if upstreamErr.StatusCode == 0 && upstreamErr.Kind != ErrUpstreamUnavailable && upstreamErr.Kind != ErrUpstreamTimeout {
+		return 0, false
+	}
+
+	if upstreamErr.StatusCode != 0 && !r.retryable[upstreamErr.StatusCode] {
+		return 0, false
+	}
+
+	if upstreamErr.RetryAfter > 0 {
+		return upstreamErr.RetryAfter, upstreamErr.RetryAfter <= r.policy.MaxDelay
+	}
+
+	delay := r.policy.BaseDelay << (attempt - 1)
+	if delay > r.policy.MaxDelay || delay <= 0 {
+		delay = r.policy.MaxDelay
+	}
+
+	if r.policy.Jitter > 0 {
+		r.randMu.Lock()
+		delay -= time.Duration(r.rand.Float64() * r.policy.Jitter * float64(delay))
+		r.randMu.Unlock()
+	}
+
+	return delay, true
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as
+// an HTTP date, returning zero when it is absent or unparseable.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(header); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
@@ -0,0 +1,319 @@
+package rick_and_morty
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordedSleeps stands in for the retrier's sleep so tests run instantly
+// while still seeing every backoff delay.
+type recordedSleeps struct {
+	mu     sync.Mutex
+	delays []time.Duration
+}
+
+func (s *recordedSleeps) sleep(ctx context.Context, d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.delays = append(s.delays, d)
+
+	return ctx.Err()
+}
+
+func newRetryingTestGateway(t *testing.T, policy RetryPolicy) (*gateway, *httpmock.MockTransport, *recordedSleeps) {
+	g, transport := newTestGateway(t, GatewayConfig{
+		MaxConcurrentPages: 1,
+		Retry:              policy,
+	})
+
+	sleeps := &recordedSleeps{}
+	g.retrier.sleep = sleeps.sleep
+
+	return g, transport, sleeps
+}
+
+// failingResponder answers with failures in order, then with success.
+func failingResponder(success httpmock.Responder, failures ...httpmock.Responder) httpmock.Responder {
+	var mu sync.Mutex
+	calls := 0
+
+	return func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		call := calls
+		calls++
+		mu.Unlock()
+
+		if call < len(failures) {
+			return failures[call](req)
+		}
+		return success(req)
+	}
+}
+
+func TestGateway_Retry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns an error when an invalid policy is passed in", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewGateway(&GatewayConfig{Retry: RetryPolicy{Jitter: 2}})
+
+		assert.EqualError(t, fmt.Errorf("invalid Retry.Jitter parameter"), err.Error())
+	})
+
+	t.Run("it does not retry by default", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport, sleeps := newRetryingTestGateway(t, RetryPolicy{})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			httpmock.NewStringResponder(503, ""))
+
+		_, err := g.GetCharacter(context.Background(), testCharacterID)
+
+		assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+		assert.Equal(t, 1, transport.GetTotalCallCount())
+		assert.Empty(t, sleeps.delays)
+	})
+
+	t.Run("it retries transport errors and retryable statuses with exponential backoff", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport, sleeps := newRetryingTestGateway(t, RetryPolicy{
+			MaxAttempts: 4,
+			BaseDelay:   10 * time.Millisecond,
+			MaxDelay:    25 * time.Millisecond,
+		})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			failingResponder(httpmock.NewStringResponder(200, `{"id": 1}`),
+				httpmock.NewErrorResponder(fmt.Errorf(testErrorText)),
+				httpmock.NewStringResponder(502, ""),
+				httpmock.NewStringResponder(503, ""),
+			))
+
+		result, err := g.GetCharacter(context.Background(), testCharacterID)
+
+		assert.Equal(t, Character{Id: 1}, result)
+		assert.Nil(t, err)
+		assert.Equal(t, 4, transport.GetTotalCallCount())
+		assert.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond}, sleeps.delays)
+	})
+
+	t.Run("it gives up after MaxAttempts", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport, _ := newRetryingTestGateway(t, RetryPolicy{MaxAttempts: 3})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			httpmock.NewStringResponder(503, ""))
+
+		_, err := g.GetCharacter(context.Background(), testCharacterID)
+
+		assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+		assert.Equal(t, 3, transport.GetTotalCallCount())
+	})
+
+	t.Run("it does not retry non-retryable statuses or decode failures", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport, _ := newRetryingTestGateway(t, RetryPolicy{MaxAttempts: 3})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/1",
+			httpmock.NewStringResponder(404, `{"error":"Character not found"}`))
+		transport.RegisterResponder("GET", defaultBaseURI+"character/2",
+			httpmock.NewStringResponder(200, `{"id": "two"}`))
+
+		_, err := g.GetCharacter(context.Background(), 1)
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		_, err = g.GetCharacter(context.Background(), 2)
+		assert.ErrorIs(t, err, ErrDecode)
+
+		assert.Equal(t, 2, transport.GetTotalCallCount())
+	})
+
+	t.Run("it only retries the configured status codes", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport, _ := newRetryingTestGateway(t, RetryPolicy{
+			MaxAttempts:          3,
+			RetryableStatusCodes: []int{500},
+		})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/1",
+			failingResponder(httpmock.NewStringResponder(200, `{"id": 1}`),
+				httpmock.NewStringResponder(500, ""),
+			))
+		transport.RegisterResponder("GET", defaultBaseURI+"character/2",
+			httpmock.NewStringResponder(503, ""))
+
+		_, err := g.GetCharacter(context.Background(), 1)
+		assert.Nil(t, err)
+
+		_, err = g.GetCharacter(context.Background(), 2)
+		assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+
+		callCounts := transport.GetCallCountInfo()
+
+		assert.Equal(t, 2, callCounts["GET "+defaultBaseURI+"character/1"])
+		assert.Equal(t, 1, callCounts["GET "+defaultBaseURI+"character/2"])
+	})
+
+	t.Run("it waits for the upstream's Retry-After", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport, sleeps := newRetryingTestGateway(t, RetryPolicy{
+			MaxAttempts: 2,
+			MaxDelay:    5 * time.Second,
+		})
+
+		rateLimited := httpmock.NewStringResponse(429, "")
+		rateLimited.Header.Set("Retry-After", "3")
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			failingResponder(httpmock.NewStringResponder(200, `{"id": 1}`),
+				httpmock.ResponderFromResponse(rateLimited),
+			))
+
+		_, err := g.GetCharacter(context.Background(), testCharacterID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []time.Duration{3 * time.Second}, sleeps.delays)
+	})
+
+	t.Run("it gives up when Retry-After exceeds MaxDelay", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport, sleeps := newRetryingTestGateway(t, RetryPolicy{
+			MaxAttempts: 2,
+			MaxDelay:    time.Second,
+		})
+
+		rateLimited := httpmock.NewStringResponse(429, "")
+		rateLimited.Header.Set("Retry-After", "60")
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			httpmock.ResponderFromResponse(rateLimited))
+
+		_, err := g.GetCharacter(context.Background(), testCharacterID)
+
+		var upstreamErr *UpstreamError
+
+		assert.ErrorAs(t, err, &upstreamErr)
+		assert.Equal(t, time.Minute, upstreamErr.RetryAfter)
+		assert.Equal(t, 1, transport.GetTotalCallCount())
+		assert.Empty(t, sleeps.delays)
+	})
+
+	t.Run("it applies jitter within the configured fraction", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := newRetrier(RetryPolicy{
+			MaxAttempts: 2,
+			BaseDelay:   100 * time.Millisecond,
+			Jitter:      0.5,
+		})
+		if err != nil {
+			t.FailNow()
+		}
+
+		for i := 0; i < 100; i++ {
+			delay, ok := r.delay(1, &UpstreamError{Kind: ErrUpstreamUnavailable, StatusCode: 503})
+
+			assert.True(t, ok)
+			assert.GreaterOrEqual(t, delay, 50*time.Millisecond)
+			assert.LessOrEqual(t, delay, 100*time.Millisecond)
+		}
+	})
+
+	t.Run("it retries a failing page without returning partial data", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport, _ := newRetryingTestGateway(t, RetryPolicy{MaxAttempts: 2})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, CharactersListResponse{
+					Info:    ApiInfo{Count: 3, Pages: 3},
+					Results: []Character{{Id: 1}},
+				})
+			})
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character?page=2",
+			failingResponder(func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, CharactersListResponse{Results: []Character{{Id: 2}}})
+			}, httpmock.NewStringResponder(502, "")))
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character?page=3",
+			func(req *http.Request) (*http.Response, error) {
+				return httpmock.NewJsonResponse(200, CharactersListResponse{Results: []Character{{Id: 3}}})
+			})
+
+		result, err := g.ListCharacters(context.Background())
+
+		assert.Equal(t, []Character{{Id: 1}, {Id: 2}, {Id: 3}}, result)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, transport.GetCallCountInfo()["GET "+defaultBaseURI+"character?page=2"])
+	})
+
+	t.Run("it stops retrying when the context is cancelled", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport, _ := newRetryingTestGateway(t, RetryPolicy{MaxAttempts: 5})
+		g.retrier.sleep = sleepContext
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			func(req *http.Request) (*http.Response, error) {
+				cancel()
+				return httpmock.NewStringResponse(503, ""), nil
+			})
+
+		_, err := g.GetCharacter(ctx, testCharacterID)
+
+		assert.ErrorIs(t, err, ErrUpstreamUnavailable)
+		assert.Equal(t, 1, transport.GetTotalCallCount())
+	})
+}
+
+func TestGateway_ParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+	assert.Equal(t, 120*time.Second, parseRetryAfter("120", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+}
@@ -0,0 +1,125 @@
+package rick_and_morty
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// errNotModified is returned by a revalidating call when the upstream
+// answered 304 to its conditional request. It never reaches a caller
+// outside the package: the CachedGateway that asked for revalidation
+// answers from its expired entry instead.
+var errNotModified = errors.New("upstream resource not modified")
+
+// validators identify the upstream response a cache entry was built from.
+type validators struct {
+	url          string
+	etag         string
+	lastModified string
+}
+
+func (v validators) empty() bool {
+	return v.etag == "" && v.lastModified == ""
+}
+
+// conditional adds the request headers that let the upstream answer 304
+// when the response v identifies is still current.
+func (v validators) conditional(req *http.Request) {
+	if v.etag != "" {
+		req.Header.Set("If-None-Match", v.etag)
+	}
+
+	if v.lastModified != "" {
+		req.Header.Set("If-Modified-Since", v.lastModified)
+	}
+}
+
+// update takes on any validators the upstream sent in header.
+func (v validators) update(header http.Header) validators {
+	if etag := header.Get("ETag"); etag != "" {
+		v.etag = etag
+	}
+
+	if lastModified := header.Get("Last-Modified"); lastModified != "" {
+		v.lastModified = lastModified
+	}
+
+	return v
+}
+
+type revalidationKey struct{}
+
+// revalidation carries validators between a CachedGateway entry and the
+// upstream requests made to refresh it. The gateway sends sent's validators
+// with the request for sent.url and records those of every successful
+// response; they are only worth keeping when the entry came from a single
+// upstream response, since a 304 for one page of a listing says nothing
+// about the others.
+type revalidation struct {
+	sent validators
+
+	mu        sync.Mutex
+	responses int
+	received  validators
+}
+
+func withRevalidation(ctx context.Context, sent validators) (context.Context, *revalidation) {
+	r := &revalidation{sent: sent}
+
+	return context.WithValue(ctx, revalidationKey{}, r), r
+}
+
+func revalidationFrom(ctx context.Context) *revalidation {
+	r, _ := ctx.Value(revalidationKey{}).(*revalidation)
+
+	return r
+}
+
+// conditional adds the stored validators to req, a request for url, if they
+// were received from that URL, and reports whether it did.
+func (r *revalidation) conditional(req *http.Request, url string) bool {
+	if r == nil || r.sent.empty() || url != r.sent.url {
+		return false
+	}
+
+	r.sent.conditional(req)
+
+	return true
+}
+
+// record notes a successful response for url.
+func (r *revalidation) record(url string, header http.Header) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.responses++
+	r.received = validators{url: url}.update(header)
+}
+
+// notModified notes that the upstream answered the conditional request
+// with a 304, taking on any validators it carries.
+func (r *revalidation) notModified(header http.Header) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.responses++
+	r.received = r.sent.update(header)
+}
+
+// result returns the validators to keep with the fetched entry, if any.
+func (r *revalidation) result() (validators, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.responses != 1 || r.received.empty() {
+		return validators{}, false
+	}
+
+	return r.received, true
+}
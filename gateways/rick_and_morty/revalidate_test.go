@@ -0,0 +1,199 @@
+package rick_and_morty
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedGateway_Revalidation(t *testing.T) {
+	t.Parallel()
+
+	const characterBody = `{"id": 1, "name": "Rick Sanchez"}`
+
+	// revalidatingResponder answers with body and header, or with a bodiless
+	// 304 when the request's conditional header matches.
+	revalidatingResponder := func(body string, header http.Header, conditional *[]http.Header) httpmock.Responder {
+		return func(req *http.Request) (*http.Response, error) {
+			*conditional = append(*conditional, http.Header{
+				"If-None-Match":     req.Header.Values("If-None-Match"),
+				"If-Modified-Since": req.Header.Values("If-Modified-Since"),
+			})
+
+			notModified := (header.Get("ETag") != "" && req.Header.Get("If-None-Match") == header.Get("ETag")) ||
+				(header.Get("Last-Modified") != "" && req.Header.Get("If-Modified-Since") == header.Get("Last-Modified"))
+
+			resp := httpmock.NewStringResponse(200, body)
+			if notModified {
+				resp = httpmock.NewStringResponse(http.StatusNotModified, "")
+			}
+
+			for name, values := range header {
+				resp.Header[name] = values
+			}
+
+			return resp, nil
+		}
+	}
+
+	t.Run("it revalidates an expired entry with If-None-Match and keeps it on a 304", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c, transport, clock := newCachedTestGateway(t, CacheConfig{GetTTL: time.Minute})
+
+		var conditional []http.Header
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			revalidatingResponder(characterBody, http.Header{"Etag": {`"v1"`}}, &conditional))
+
+		_, err := c.GetCharacter(context.Background(), testCharacterID)
+		assert.Nil(t, err)
+
+		clock.Advance(time.Minute)
+
+		result, err := c.GetCharacter(context.Background(), testCharacterID)
+
+		assert.Equal(t, Character{Id: 1, Name: "Rick Sanchez"}, result)
+		assert.Nil(t, err)
+		assert.Empty(t, conditional[0].Get("If-None-Match"))
+		assert.Equal(t, `"v1"`, conditional[1].Get("If-None-Match"))
+
+		_, err = c.GetCharacter(context.Background(), testCharacterID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, transport.GetTotalCallCount())
+		assert.Equal(t, CacheStats{Hits: 1, Misses: 2, Entries: 1}, c.Stats())
+	})
+
+	t.Run("it revalidates with If-Modified-Since", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c, transport, clock := newCachedTestGateway(t, CacheConfig{GetTTL: time.Minute})
+
+		var conditional []http.Header
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			revalidatingResponder(characterBody, http.Header{"Last-Modified": {"Sat, 04 Nov 2017 18:48:46 GMT"}}, &conditional))
+
+		for i := 0; i < 3; i++ {
+			result, err := c.GetCharacter(context.Background(), testCharacterID)
+
+			assert.Equal(t, Character{Id: 1, Name: "Rick Sanchez"}, result)
+			assert.Nil(t, err)
+
+			clock.Advance(time.Minute)
+		}
+
+		assert.Equal(t, 3, transport.GetTotalCallCount())
+		assert.Equal(t, "Sat, 04 Nov 2017 18:48:46 GMT", conditional[2].Get("If-Modified-Since"))
+		assert.Empty(t, conditional[2].Get("If-None-Match"))
+	})
+
+	t.Run("it replaces an expired entry the upstream has changed", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c, transport, clock := newCachedTestGateway(t, CacheConfig{GetTTL: time.Minute})
+
+		var conditional []http.Header
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			revalidatingResponder(characterBody, http.Header{"Etag": {`"v1"`}}, &conditional))
+
+		_, err := c.GetCharacter(context.Background(), testCharacterID)
+		assert.Nil(t, err)
+
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			revalidatingResponder(`{"id": 1, "name": "Evil Rick"}`, http.Header{"Etag": {`"v2"`}}, &conditional))
+
+		for i := 0; i < 2; i++ {
+			clock.Advance(time.Minute)
+
+			result, err := c.GetCharacter(context.Background(), testCharacterID)
+
+			assert.Equal(t, Character{Id: 1, Name: "Evil Rick"}, result)
+			assert.Nil(t, err)
+		}
+
+		assert.Equal(t, `"v1"`, conditional[1].Get("If-None-Match"))
+		assert.Equal(t, `"v2"`, conditional[2].Get("If-None-Match"))
+	})
+
+	t.Run("it sends no conditional headers for responses without validators", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c, transport, clock := newCachedTestGateway(t, CacheConfig{GetTTL: time.Minute})
+
+		var conditional []http.Header
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			revalidatingResponder(characterBody, http.Header{}, &conditional))
+
+		for i := 0; i < 2; i++ {
+			_, err := c.GetCharacter(context.Background(), testCharacterID)
+			assert.Nil(t, err)
+
+			clock.Advance(time.Minute)
+		}
+
+		assert.Equal(t, http.Header{"If-None-Match": nil, "If-Modified-Since": nil}, conditional[1])
+	})
+
+	t.Run("it does not revalidate a listing built from several pages", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		c, transport, clock := newCachedTestGateway(t, CacheConfig{ListTTL: time.Minute})
+
+		var conditional []http.Header
+		transport.RegisterResponder("GET", defaultBaseURI+"character",
+			revalidatingResponder(`{"info": {"count": 2, "pages": 2}, "results": [{"id": 1}]}`, http.Header{"Etag": {`"page-1"`}}, &conditional))
+		transport.RegisterResponder("GET", defaultBaseURI+"character?page=2",
+			revalidatingResponder(`{"info": {"count": 2, "pages": 2}, "results": [{"id": 2}]}`, http.Header{"Etag": {`"page-2"`}}, &conditional))
+
+		for i := 0; i < 2; i++ {
+			result, err := c.ListCharacters(context.Background())
+
+			assert.Equal(t, []Character{{Id: 1}, {Id: 2}}, result)
+			assert.Nil(t, err)
+
+			clock.Advance(time.Minute)
+		}
+
+		assert.Equal(t, 4, transport.GetTotalCallCount())
+		for _, header := range conditional {
+			assert.Empty(t, header.Get("If-None-Match"))
+		}
+	})
+
+	t.Run("it makes no conditional requests outside the cache", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		g, transport := newTestGateway(t, GatewayConfig{})
+
+		var conditional []http.Header
+		transport.RegisterResponder("GET", defaultBaseURI+"character/"+strconv.Itoa(testCharacterID),
+			revalidatingResponder(characterBody, http.Header{"Etag": {`"v1"`}}, &conditional))
+
+		for i := 0; i < 2; i++ {
+			result, err := g.GetCharacter(context.Background(), testCharacterID)
+
+			assert.Equal(t, Character{Id: 1, Name: "Rick Sanchez"}, result)
+			assert.Nil(t, err)
+		}
+
+		assert.Empty(t, conditional[1].Get("If-None-Match"))
+	})
+}
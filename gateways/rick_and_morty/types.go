@@ -1,12 +1,50 @@
 package rick_and_morty
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type Gateway interface {
-	GetCharacter(id string) (Character, error)
-	GetCharacters(ids string) ([]Character, error)
-	SearchCharacters(name string) ([]Character, error)
-	ListCharacters() ([]Character, error)
+	GetCharacter(ctx context.Context, id int) (Character, error)
+	GetCharacters(ctx context.Context, ids []int) (CharacterBatch, error)
+	SearchCharacters(ctx context.Context, filter CharacterFilter) ([]Character, error)
+	ListCharacters(ctx context.Context) ([]Character, error)
+	SearchCharacterPages(ctx context.Context, filter CharacterFilter) CharacterIterator
+	ListCharacterPages(ctx context.Context) CharacterIterator
+	GetEpisode(ctx context.Context, id int) (Episode, error)
+	GetEpisodes(ctx context.Context, ids []int) ([]Episode, error)
+	SearchEpisodes(ctx context.Context, filter EpisodeFilter) ([]Episode, error)
+	ListEpisodes(ctx context.Context) ([]Episode, error)
+	GetLocation(ctx context.Context, id int) (Location, error)
+	GetLocations(ctx context.Context, ids []int) ([]Location, error)
+	SearchLocations(ctx context.Context, filter LocationFilter) ([]Location, error)
+	ListLocations(ctx context.Context) ([]Location, error)
+	CircuitState() CircuitState
+	// Close releases the gateway's resources, such as idle upstream
+	// connections. Calls already in flight are not interrupted.
+	Close() error
+}
+
+// CharacterIterator walks a character listing one upstream page at a time,
+// in the style of bufio.Scanner:
+//
+//	for it.Next() {
+//		use(it.Page())
+//	}
+//	if err := it.Err(); err != nil { ... }
+type CharacterIterator interface {
+	Next() bool
+	Page() []Character
+	Info() ApiInfo
+	Err() error
+}
+
+// CharacterBatch is the result of a multi-get: the characters found, in the
+// order their ids were requested, and the requested ids that do not exist.
+type CharacterBatch struct {
+	Characters []Character
+	Missing    []int
 }
 
 type Character struct {
@@ -30,6 +68,58 @@ type Character struct {
 	Created time.Time `json:"created"`
 }
 
+// CharacterFilter narrows a character search. Empty fields are not filtered
+// on; Status and Gender are expected to hold one of the values upstream
+// accepts (see CharacterStatuses and CharacterGenders).
+type CharacterFilter struct {
+	Name    string
+	Status  string
+	Species string
+	Type    string
+	Gender  string
+}
+
+// CharacterStatuses and CharacterGenders list the values upstream accepts for
+// the matching CharacterFilter fields.
+var (
+	CharacterStatuses = []string{"alive", "dead", "unknown"}
+	CharacterGenders  = []string{"female", "male", "genderless", "unknown"}
+)
+
+type Episode struct {
+	Id         int       `json:"id"`
+	Name       string    `json:"name"`
+	AirDate    string    `json:"air_date"`
+	Episode    string    `json:"episode"`
+	Characters []string  `json:"characters"`
+	Url        string    `json:"url"`
+	Created    time.Time `json:"created"`
+}
+
+// EpisodeFilter narrows an episode search. Empty fields are not filtered on;
+// Episode matches episode codes such as "S01E01", or prefixes like "S01".
+type EpisodeFilter struct {
+	Name    string
+	Episode string
+}
+
+type Location struct {
+	Id        int       `json:"id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Dimension string    `json:"dimension"`
+	Residents []string  `json:"residents"`
+	Url       string    `json:"url"`
+	Created   time.Time `json:"created"`
+}
+
+// LocationFilter narrows a location search. Empty fields are not filtered on.
+type LocationFilter struct {
+	Name      string
+	Type      string
+	Dimension string
+}
+
 type ApiInfo struct {
 	Count int     `json:"count,omitempty"`
 	Pages int     `json:"pages,omitempty"`
@@ -41,3 +131,9 @@ type CharactersListResponse struct {
 	Info    ApiInfo
 	Results []Character `json:"results,omitempty"`
 }
+
+// listResponse is the envelope shared by every paginated upstream listing.
+type listResponse[T any] struct {
+	Info    ApiInfo
+	Results []T `json:"results,omitempty"`
+}
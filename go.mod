@@ -9,11 +9,12 @@ require (
 	github.com/golang/mock v1.6.0
 	github.com/jarcoal/httpmock v1.3.0
 	github.com/stretchr/testify v1.8.4
+	golang.org/x/time v0.5.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/ajg/form v1.5.1 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
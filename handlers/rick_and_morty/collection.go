@@ -0,0 +1,171 @@
+package rick_and_morty
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gojo/gateways/rick_and_morty"
+	"gojo/utilities"
+)
+
+const (
+	sortByID       = "id"
+	sortByName     = "name"
+	sortByCreated  = "created"
+	sortByEpisodes = "episodes"
+)
+
+var (
+	sortFields = []string{sortByID, sortByName, sortByCreated, sortByEpisodes}
+
+	errSortWhileStreaming = invalidParams(invalidParam("sort", "", "sorting is not supported when streaming"))
+)
+
+type sortKey struct {
+	field      string
+	descending bool
+}
+
+// localFilter holds the predicates applied after the upstream has answered.
+// Zero values do not filter.
+type localFilter struct {
+	origin      string
+	location    string
+	minEpisodes int
+}
+
+// collectionOptions holds the sort= and local filter parameters shared by the
+// character list, search and multi-get handlers.
+type collectionOptions struct {
+	sort   []sortKey
+	filter localFilter
+}
+
+// parseCollectionOptions reads sort (a comma-separated list of fields, each
+// optionally prefixed with "-" for descending order), origin, location and
+// min_episodes from the query string.
+func parseCollectionOptions(r *http.Request) (collectionOptions, error) {
+	query := r.URL.Query()
+	options := collectionOptions{
+		filter: localFilter{
+			origin:   strings.ToLower(strings.TrimSpace(query.Get("origin"))),
+			location: strings.ToLower(strings.TrimSpace(query.Get("location"))),
+		},
+	}
+
+	var problems []utilities.InvalidParam
+
+	if raw := query.Get("sort"); raw != "" {
+		for _, token := range strings.Split(raw, ",") {
+			key := sortKey{field: strings.TrimSpace(token)}
+			if strings.HasPrefix(key.field, "-") {
+				key.field, key.descending = key.field[1:], true
+			}
+
+			if !oneOf(key.field, sortFields) {
+				problems = append(problems, invalidEnumParameter("sort", token, sortFields))
+				continue
+			}
+
+			options.sort = append(options.sort, key)
+		}
+	}
+
+	if raw := query.Get("min_episodes"); raw != "" {
+		minEpisodes, err := strconv.Atoi(raw)
+		if err != nil || minEpisodes < 0 {
+			problems = append(problems, invalidParam("min_episodes", raw, "must be a non-negative integer"))
+		}
+		options.filter.minEpisodes = minEpisodes
+	}
+
+	if len(problems) > 0 {
+		return collectionOptions{}, invalidParams(problems...)
+	}
+
+	return options, nil
+}
+
+// apply filters and then sorts characters, returning a new slice. Sorting is
+// stable, so characters that tie on every key keep their upstream order.
+func (o collectionOptions) apply(characters []rick_and_morty.Character) []rick_and_morty.Character {
+	result := make([]rick_and_morty.Character, 0, len(characters))
+	for _, character := range characters {
+		if o.filter.match(character) {
+			result = append(result, character)
+		}
+	}
+
+	if len(o.sort) > 0 {
+		sort.SliceStable(result, func(i, j int) bool {
+			return o.less(result[i], result[j])
+		})
+	}
+
+	return result
+}
+
+func (o collectionOptions) less(a, b rick_and_morty.Character) bool {
+	for _, key := range o.sort {
+		cmp := compareCharacters(a, b, key.field)
+		if cmp == 0 {
+			continue
+		}
+
+		if key.descending {
+			return cmp > 0
+		}
+		return cmp < 0
+	}
+
+	return false
+}
+
+func compareCharacters(a, b rick_and_morty.Character, field string) int {
+	switch field {
+	case sortByID:
+		return compareInts(a.Id, b.Id)
+	case sortByName:
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	case sortByCreated:
+		switch {
+		case a.Created.Before(b.Created):
+			return -1
+		case a.Created.After(b.Created):
+			return 1
+		}
+	case sortByEpisodes:
+		return compareInts(len(a.Episode), len(b.Episode))
+	}
+
+	return 0
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+// match reports whether character passes every local filter. Origin and
+// location match case-insensitively on any part of the name, so "earth"
+// finds both "Earth (C-137)" and "Earth (Replacement Dimension)".
+func (f localFilter) match(character rick_and_morty.Character) bool {
+	switch {
+	case f.origin != "" && !strings.Contains(strings.ToLower(character.Origin.Name), f.origin):
+		return false
+	case f.location != "" && !strings.Contains(strings.ToLower(character.Location.Name), f.location):
+		return false
+	case len(character.Episode) < f.minEpisodes:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,202 @@
+package rick_and_morty
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gojo/gateways/rick_and_morty"
+	mockGateway "gojo/gateways/rick_and_morty/mock_gateway"
+)
+
+func testCollectionCharacters() []rick_and_morty.Character {
+	created, _ := time.Parse(time.RFC3339, "2017-11-04T18:48:46.250Z")
+
+	characters := []rick_and_morty.Character{
+		{Id: 1, Name: "Rick Sanchez", Episode: []string{"1", "2", "3"}, Created: created},
+		{Id: 2, Name: "Morty Smith", Episode: []string{"1", "2", "3"}, Created: created.Add(time.Hour)},
+		{Id: 3, Name: "summer Smith", Episode: []string{"1"}, Created: created.Add(-time.Hour)},
+		{Id: 4, Name: "Abradolf Lincler", Episode: []string{"1", "2"}, Created: created},
+	}
+
+	characters[0].Origin.Name, characters[0].Location.Name = "Earth (C-137)", "Citadel of Ricks"
+	characters[1].Origin.Name, characters[1].Location.Name = "unknown", "Citadel of Ricks"
+	characters[2].Origin.Name, characters[2].Location.Name = "Earth (Replacement Dimension)", "Earth (Replacement Dimension)"
+	characters[3].Origin.Name, characters[3].Location.Name = "Earth (Replacement Dimension)", "Testicle Monster Dimension"
+
+	return characters
+}
+
+func characterIDs(characters []rick_and_morty.Character) []int {
+	ids := make([]int, len(characters))
+	for i, character := range characters {
+		ids[i] = character.Id
+	}
+
+	return ids
+}
+
+func TestHandler_CollectionOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it sorts the list by several keys", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().ListCharacters(gomock.Any()).Return(testCollectionCharacters(), nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/list", h.ListCharacters, "/characters/list?sort=-episodes,name", nil)
+
+		response := ListCharactersResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []int{2, 1, 4, 3}, characterIDs(response.Data))
+	})
+
+	t.Run("it sorts by creation time and filters before paginating", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().SearchCharacters(gomock.Any(), rick_and_morty.CharacterFilter{Name: "Smith"}).Return(testCollectionCharacters(), nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/search", h.SearchCharacters, "/characters/search?name=Smith&origin=earth&sort=-created&per_page=1", nil)
+
+		response := ListCharactersResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []int{1}, characterIDs(response.Data))
+		assert.Equal(t, 3, response.Meta.Count)
+	})
+
+	t.Run("it filters multi-get results by location and episode count", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetCharacters(gomock.Any(), []int{1, 2, 3, 4}).Return(rick_and_morty.CharacterBatch{Characters: testCollectionCharacters()}, nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/get/{ids}", h.GetCharacters, "/characters/get/1,2,3,4?location=CITADEL&min_episodes=3&sort=-id", nil)
+
+		response := ListCharactersResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []int{2, 1}, characterIDs(response.Data))
+		assert.Nil(t, response.Meta)
+	})
+
+	t.Run("it applies local filters while streaming", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		characters := testCollectionCharacters()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().ListCharacterPages(gomock.Any()).Return(&fakeCharacterIterator{
+			pages: [][]rick_and_morty.Character{characters[:2], characters[2:]},
+		})
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/list", h.ListCharacters, "/characters/list?stream=ndjson&min_episodes=2", nil)
+
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Len(t, lines, 3)
+	})
+
+	for target, expectedError := range map[string]string{
+		"/characters/list?sort=name,-age":          `invalid sort parameter "-age": must be one of id, name, created, episodes`,
+		"/characters/list?min_episodes=lots":       `invalid min_episodes parameter "lots": must be a non-negative integer`,
+		"/characters/list?sort=name&stream=true":   errSortWhileStreaming.Error(),
+		"/characters/list?sort=up&min_episodes=-1": `invalid sort parameter "up": must be one of id, name, created, episodes; invalid min_episodes parameter "-1": must be a non-negative integer`,
+	} {
+		target, expectedError := target, expectedError
+
+		t.Run(fmt.Sprintf("it returns an error for %s", target), func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			h, err := NewHandler(&HandlerConfig{
+				ApiClient: mockGateway.NewMockGateway(ctrl),
+			})
+
+			if err != nil {
+				t.FailNow()
+			}
+
+			rec := serveTestRequest(t, "/characters/list", h.ListCharacters, target, nil)
+
+			response := errorBody{}
+
+			err = json.Unmarshal(rec.Body.Bytes(), &response)
+			if err != nil {
+				t.FailNow()
+			}
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, expectedError, response.Error)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package rick_and_morty
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"gojo/gateways/rick_and_morty"
+)
+
+const defaultCacheControl = "public, max-age=60"
+
+// bufferedResponse holds a rendered response back so validators can be
+// computed from its body before anything reaches the client.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponse() *bufferedResponse {
+	return &bufferedResponse{header: http.Header{}, status: http.StatusOK}
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	b.status = status
+}
+
+// writeConditional sends a rendered character response. Successful responses
+// get a strong ETag over the body, a Last-Modified from the newest character
+// and the handler's Cache-Control, and become a bodiless 304 when the
+// request's If-None-Match or If-Modified-Since shows the client is up to
+// date. Anything else, such as an error, is passed on untouched.
+func (h *handler) writeConditional(w http.ResponseWriter, r *http.Request, buffered *bufferedResponse, characters []rick_and_morty.Character) {
+	// Appending keeps what middleware has already set, such as the CORS
+	// Vary: Origin, alongside the handler's own values.
+	for name, values := range buffered.header {
+		w.Header()[name] = append(w.Header()[name], values...)
+	}
+
+	if buffered.status != http.StatusOK {
+		w.WriteHeader(buffered.status)
+		_, _ = w.Write(buffered.body.Bytes())
+		return
+	}
+
+	sum := sha256.Sum256(buffered.body.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	lastModified := newestCreated(characters)
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", h.cacheControl)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(r, etag, lastModified) {
+		w.Header().Del("Content-Type")
+		w.Header().Del("Content-Length")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	_, _ = w.Write(buffered.body.Bytes())
+}
+
+// notModified evaluates If-None-Match, falling back to If-Modified-Since only
+// when the former is absent, as RFC 7232 requires. If-None-Match uses the weak
+// comparison, so W/ prefixes on the client's tags are ignored.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, tag := range strings.Split(ifNoneMatch, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	// HTTP dates have whole-second precision.
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+func newestCreated(characters []rick_and_morty.Character) time.Time {
+	var newest time.Time
+
+	for _, character := range characters {
+		if character.Created.After(newest) {
+			newest = character.Created
+		}
+	}
+
+	return newest
+}
@@ -0,0 +1,111 @@
+package rick_and_morty
+
+import (
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"gojo/gateways/rick_and_morty"
+)
+
+// episodeCodePattern accepts a full episode code such as "S01E01", or a
+// season prefix such as "S01" to match every episode in it.
+var episodeCodePattern = regexp.MustCompile(`^[Ss]\d{1,2}([Ee]\d{1,2})?$`)
+
+func (h *handler) GetEpisode(w http.ResponseWriter, r *http.Request) {
+	episodeIDParam := chi.URLParam(r, "id")
+
+	if episodeIDParam == "" {
+		renderMissingParameter(w, r, "id")
+		return
+	}
+
+	episodeID, err := parseID("id", episodeIDParam)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	episode, err := h.apiClient.GetEpisode(r.Context(), episodeID)
+	if err != nil {
+		log.Println(err)
+		renderGatewayError(w, r, err)
+		return
+	}
+
+	response := EpisodeResponse{
+		Data: episode,
+	}
+
+	render.JSON(w, r, response)
+}
+
+func (h *handler) GetEpisodes(w http.ResponseWriter, r *http.Request) {
+	episodeIDs, err := parseIDs("ids", chi.URLParam(r, "ids"), h.maxBatchIDs)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	episodeList, err := h.apiClient.GetEpisodes(r.Context(), episodeIDs)
+	if err != nil {
+		log.Println(err)
+		renderGatewayError(w, r, err)
+		return
+	}
+
+	response := ListEpisodesResponse{
+		Data: episodeList,
+	}
+
+	render.JSON(w, r, response)
+}
+
+func (h *handler) SearchEpisodes(w http.ResponseWriter, r *http.Request) {
+	filter := rick_and_morty.EpisodeFilter{
+		Name:    strings.TrimSpace(r.URL.Query().Get("name")),
+		Episode: strings.TrimSpace(r.URL.Query().Get("episode")),
+	}
+
+	if filter.Name == "" && filter.Episode == "" {
+		renderMissingParameter(w, r, "name", "episode")
+		return
+	}
+
+	if filter.Episode != "" && !episodeCodePattern.MatchString(filter.Episode) {
+		renderValidationError(w, r, invalidParams(invalidParam("episode", filter.Episode, "must be a season such as S01 or an episode such as S01E01")))
+		return
+	}
+
+	episodeList, err := h.apiClient.SearchEpisodes(r.Context(), filter)
+	if err != nil {
+		log.Println(err)
+		renderGatewayError(w, r, err)
+		return
+	}
+
+	response := ListEpisodesResponse{
+		Data: episodeList,
+	}
+
+	render.JSON(w, r, response)
+}
+
+func (h *handler) ListEpisodes(w http.ResponseWriter, r *http.Request) {
+	episodeList, err := h.apiClient.ListEpisodes(r.Context())
+	if err != nil {
+		log.Println(err)
+		renderGatewayError(w, r, err)
+		return
+	}
+
+	response := ListEpisodesResponse{
+		Data: episodeList,
+	}
+
+	render.JSON(w, r, response)
+}
@@ -0,0 +1,307 @@
+package rick_and_morty
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gojo/gateways/rick_and_morty"
+	mockGateway "gojo/gateways/rick_and_morty/mock_gateway"
+)
+
+const (
+	testEpisodeID          = "1"
+	testMultipleEpisodeIDs = "1,2"
+)
+
+func testEpisodes() []rick_and_morty.Episode {
+	pilotTime, _ := time.Parse(time.RFC3339, "2017-11-10T12:56:33.798Z")
+	lawnMowerDogTime, _ := time.Parse(time.RFC3339, "2017-11-10T12:56:33.916Z")
+
+	return []rick_and_morty.Episode{
+		{
+			Id:         1,
+			Name:       "Pilot",
+			AirDate:    "December 2, 2013",
+			Episode:    "S01E01",
+			Characters: []string{"https://rickandmortyapi.com/api/character/1"},
+			Url:        "https://rickandmortyapi.com/api/episode/1",
+			Created:    pilotTime,
+		},
+		{
+			Id:         2,
+			Name:       "Lawnmower Dog",
+			AirDate:    "December 9, 2013",
+			Episode:    "S01E02",
+			Characters: []string{"https://rickandmortyapi.com/api/character/1"},
+			Url:        "https://rickandmortyapi.com/api/episode/2",
+			Created:    lawnMowerDogTime,
+		},
+	}
+}
+
+func TestHandler_GetEpisode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns an error when the API Client returns an error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetEpisode(gomock.Any(), 1).Return(rick_and_morty.Episode{}, fmt.Errorf(testErrorText))
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/episodes/{id}", h.GetEpisode, fmt.Sprintf("/episodes/%s", testEpisodeID), nil)
+
+		response := errorBody{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, testErrorText, response.Error)
+	})
+
+	t.Run("it successfully returns an Episode", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expectedEpisode := testEpisodes()[0]
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetEpisode(gomock.Any(), 1).Return(expectedEpisode, nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/episodes/{id}", h.GetEpisode, fmt.Sprintf("/episodes/%s", testEpisodeID), nil)
+
+		response := EpisodeResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, EpisodeResponse{Data: expectedEpisode}, response)
+	})
+}
+
+func TestHandler_GetEpisodes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns an error when the API Client returns an error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetEpisodes(gomock.Any(), []int{1, 2}).Return([]rick_and_morty.Episode{}, fmt.Errorf(testErrorText))
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/episodes/get/{ids}", h.GetEpisodes, fmt.Sprintf("/episodes/get/%s", testMultipleEpisodeIDs), nil)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("it successfully returns a list of episodes", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expectedEpisodes := testEpisodes()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetEpisodes(gomock.Any(), []int{1, 2}).Return(expectedEpisodes, nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/episodes/get/{ids}", h.GetEpisodes, fmt.Sprintf("/episodes/get/%s", testMultipleEpisodeIDs), nil)
+
+		response := ListEpisodesResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, ListEpisodesResponse{Data: expectedEpisodes}, response)
+	})
+}
+
+func TestHandler_SearchEpisodes(t *testing.T) {
+	t.Parallel()
+
+	for _, target := range []string{"/episodes/search", "/episodes/search?name=+", "/episodes/search?episode=Season1", "/episodes/search?episode=S01E001"} {
+		target := target
+
+		t.Run(fmt.Sprintf("it returns an error for %s", target), func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			h, err := NewHandler(&HandlerConfig{
+				ApiClient: mockGateway.NewMockGateway(ctrl),
+			})
+
+			if err != nil {
+				t.FailNow()
+			}
+
+			rec := serveTestRequest(t, "/episodes/search", h.SearchEpisodes, target, nil)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+
+	t.Run("it returns an error when the API Client returns an error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().SearchEpisodes(gomock.Any(), rick_and_morty.EpisodeFilter{Name: "Pilot"}).Return([]rick_and_morty.Episode{}, fmt.Errorf(testErrorText))
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/episodes/search", h.SearchEpisodes, "/episodes/search?name=Pilot", nil)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("it successfully returns a list of episodes", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expectedEpisodes := testEpisodes()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().SearchEpisodes(gomock.Any(), rick_and_morty.EpisodeFilter{Name: "Lawnmower Dog", Episode: "s01"}).Return(expectedEpisodes[1:], nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/episodes/search", h.SearchEpisodes, "/episodes/search?name=Lawnmower%20Dog&episode=s01", nil)
+
+		response := ListEpisodesResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, ListEpisodesResponse{Data: expectedEpisodes[1:]}, response)
+	})
+}
+
+func TestHandler_ListEpisodes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns an error when the API Client returns an error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().ListEpisodes(gomock.Any()).Return([]rick_and_morty.Episode{}, fmt.Errorf(testErrorText))
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/episodes/list", h.ListEpisodes, "/episodes/list", nil)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("it successfully returns a list of episodes", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expectedEpisodes := testEpisodes()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().ListEpisodes(gomock.Any()).Return(expectedEpisodes, nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/episodes/list", h.ListEpisodes, "/episodes/list", nil)
+
+		response := ListEpisodesResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, ListEpisodesResponse{Data: expectedEpisodes}, response)
+	})
+}
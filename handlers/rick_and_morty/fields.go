@@ -0,0 +1,174 @@
+package rick_and_morty
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-chi/render"
+
+	"gojo/gateways/rick_and_morty"
+	"gojo/utilities"
+)
+
+// characterFields lists every JSON path a fields= parameter may name, such
+// as "name" or "origin.name", derived from the Character struct tags.
+var characterFields = jsonPaths(reflect.TypeOf(rick_and_morty.Character{}), "")
+
+// fieldset is the tree of JSON members to keep. A nil child keeps the whole
+// member; a non-nil child keeps only the members it names in turn. A nil
+// fieldset keeps everything.
+type fieldset map[string]fieldset
+
+// parseFieldset reads the comma-separated fields query parameter, naming
+// every unknown field in the error.
+func parseFieldset(r *http.Request) (fieldset, error) {
+	raw := r.URL.Query().Get("fields")
+	if raw == "" {
+		return nil, nil
+	}
+
+	fields := fieldset{}
+	var problems []utilities.InvalidParam
+
+	for _, token := range strings.Split(raw, ",") {
+		path := strings.TrimSpace(token)
+		if path == "" {
+			continue
+		}
+
+		if !oneOf(path, characterFields) {
+			problems = append(problems, invalidEnumParameter("fields", token, characterFields))
+			continue
+		}
+
+		fields.add(strings.Split(path, "."))
+	}
+
+	if len(problems) > 0 {
+		return nil, invalidParams(problems...)
+	}
+
+	return fields, nil
+}
+
+func (f fieldset) add(path []string) {
+	child, seen := f[path[0]]
+
+	switch {
+	case len(path) == 1:
+		f[path[0]] = nil
+	case seen && child == nil:
+		// The whole member is already selected.
+	default:
+		if child == nil {
+			child = fieldset{}
+			f[path[0]] = child
+		}
+		child.add(path[1:])
+	}
+}
+
+// project trims a JSON object, or every object in a JSON array, down to the
+// members in f.
+func (f fieldset) project(raw json.RawMessage) (json.RawMessage, error) {
+	if f == nil {
+		return raw, nil
+	}
+
+	trimmed := bytes.TrimSpace(raw)
+
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var items []json.RawMessage
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, err
+		}
+
+		for i, item := range items {
+			projected, err := f.project(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = projected
+		}
+
+		return json.Marshal(items)
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &members); err != nil {
+		return nil, err
+	}
+
+	kept := make(map[string]json.RawMessage, len(f))
+	for name, child := range f {
+		value, ok := members[name]
+		if !ok {
+			continue
+		}
+
+		projected, err := child.project(value)
+		if err != nil {
+			return nil, err
+		}
+		kept[name] = projected
+	}
+
+	return json.Marshal(kept)
+}
+
+// renderCharacterJSON renders response, a CharacterResponse or
+// ListCharactersResponse, keeping only the requested fields of its data.
+func renderCharacterJSON(w http.ResponseWriter, r *http.Request, response interface{}, fields fieldset) {
+	if fields == nil {
+		render.JSON(w, r, response)
+		return
+	}
+
+	body, err := json.Marshal(response)
+	if err == nil {
+		var envelope map[string]json.RawMessage
+		err = json.Unmarshal(body, &envelope)
+		if data, ok := envelope["data"]; ok && err == nil {
+			envelope["data"], err = fields.project(data)
+		}
+
+		if err == nil {
+			render.JSON(w, r, envelope)
+			return
+		}
+	}
+
+	log.Println(err)
+	utilities.RenderError(w, r, utilities.CodeInternal, err)
+}
+
+// jsonPaths walks the exported, JSON-tagged fields of t, descending into
+// nested structs other than time.Time.
+func jsonPaths(t reflect.Type, prefix string) []string {
+	var paths []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		paths = append(paths, prefix+name)
+
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Time{}) {
+			paths = append(paths, jsonPaths(field.Type, prefix+name+".")...)
+		}
+	}
+
+	return paths
+}
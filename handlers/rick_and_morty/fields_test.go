@@ -0,0 +1,152 @@
+package rick_and_morty
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gojo/gateways/rick_and_morty"
+	mockGateway "gojo/gateways/rick_and_morty/mock_gateway"
+)
+
+func TestHandler_SparseFieldsets(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it trims a single character to the requested fields", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		character := testCollectionCharacters()[0]
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetCharacter(gomock.Any(), 1).Return(character, nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/{id}", h.GetCharacter, "/characters/1?fields=name,origin.name,id", nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"data":{"id":1,"name":"Rick Sanchez","origin":{"name":"Earth (C-137)"}}}`, rec.Body.String())
+	})
+
+	t.Run("it keeps the whole nested object when it is requested alongside one of its fields", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetCharacters(gomock.Any(), []int{1, 2}).Return(rick_and_morty.CharacterBatch{Characters: testCollectionCharacters()[:2]}, nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/get/{ids}", h.GetCharacters, "/characters/get/1,2?fields=location.name,location", nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"data":[
+			{"location":{"name":"Citadel of Ricks","url":""}},
+			{"location":{"name":"Citadel of Ricks","url":""}}
+		]}`, rec.Body.String())
+	})
+
+	t.Run("it leaves the pagination envelope intact", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().ListCharacters(gomock.Any()).Return(testCollectionCharacters()[:1], nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/list", h.ListCharacters, "/characters/list?fields=id", nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{
+			"data":[{"id":1}],
+			"meta":{"count":1,"pages":1,"page":1,"per_page":20},
+			"links":{
+				"self":"/characters/list?fields=id&page=1&per_page=20",
+				"first":"/characters/list?fields=id&page=1&per_page=20",
+				"last":"/characters/list?fields=id&page=1&per_page=20"
+			}
+		}`, rec.Body.String())
+	})
+
+	t.Run("it trims streamed characters", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().ListCharacterPages(gomock.Any()).Return(&fakeCharacterIterator{
+			pages: [][]rick_and_morty.Character{testCollectionCharacters()[:2]},
+		})
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/list", h.ListCharacters, "/characters/list?stream=ndjson&fields=id", nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "{\"id\":1}\n{\"id\":2}", strings.TrimSpace(rec.Body.String()))
+	})
+
+	t.Run("it returns an error naming each unknown field", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: mockGateway.NewMockGateway(ctrl),
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/{id}", h.GetCharacter, "/characters/1?fields=name,age,origin.dimension", nil)
+
+		response := errorBody{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, response.Error, `invalid fields parameter "age"`)
+		assert.Contains(t, response.Error, `invalid fields parameter "origin.dimension"`)
+		assert.NotContains(t, response.Error, `"name"`)
+	})
+}
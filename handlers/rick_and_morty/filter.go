@@ -0,0 +1,38 @@
+package rick_and_morty
+
+import (
+	"net/url"
+	"strings"
+
+	"gojo/gateways/rick_and_morty"
+	"gojo/utilities"
+)
+
+// characterFilter reads the character search parameters from query. Status
+// and gender are matched case-insensitively against the values upstream
+// accepts; every parameter that fails validation is named in the error.
+func characterFilter(query url.Values) (rick_and_morty.CharacterFilter, error) {
+	filter := rick_and_morty.CharacterFilter{
+		Name:    strings.TrimSpace(query.Get("name")),
+		Status:  strings.ToLower(strings.TrimSpace(query.Get("status"))),
+		Species: strings.TrimSpace(query.Get("species")),
+		Type:    strings.TrimSpace(query.Get("type")),
+		Gender:  strings.ToLower(strings.TrimSpace(query.Get("gender"))),
+	}
+
+	var problems []utilities.InvalidParam
+
+	if filter.Status != "" && !oneOf(filter.Status, rick_and_morty.CharacterStatuses) {
+		problems = append(problems, invalidEnumParameter("status", query.Get("status"), rick_and_morty.CharacterStatuses))
+	}
+
+	if filter.Gender != "" && !oneOf(filter.Gender, rick_and_morty.CharacterGenders) {
+		problems = append(problems, invalidEnumParameter("gender", query.Get("gender"), rick_and_morty.CharacterGenders))
+	}
+
+	if len(problems) > 0 {
+		return rick_and_morty.CharacterFilter{}, invalidParams(problems...)
+	}
+
+	return filter, nil
+}
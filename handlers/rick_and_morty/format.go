@@ -0,0 +1,440 @@
+package rick_and_morty
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gojo/gateways/rick_and_morty"
+	"gojo/utilities"
+)
+
+// xmlItemNames names the elements of JSON arrays in XML output after the
+// member holding them; arrays not listed here use "item".
+var xmlItemNames = map[string]string{
+	"data":      "character",
+	"episodes":  "episode",
+	"locations": "location",
+	"episode":   "url",
+	"missing":   "id",
+}
+
+// renderCharacters renders response, a CharacterResponse or
+// ListCharactersResponse, in format, keeping only the requested fields of its
+// data, and sends it with caching validators. NDJSON and CSV carry just the
+// characters, so the pagination of list responses moves into Link and
+// X-Total-Count headers.
+func (h *handler) renderCharacters(w http.ResponseWriter, r *http.Request, format string, response interface{}, fields fieldset) {
+	var characters []rick_and_morty.Character
+
+	buffered := newBufferedResponse()
+	buffered.Header().Add("Vary", "Accept")
+
+	switch response := response.(type) {
+	case CharacterResponse:
+		characters = []rick_and_morty.Character{response.Data}
+	case ListCharactersResponse:
+		characters = response.Data
+		if format == formatNDJSON || format == formatCSV {
+			setPageHeaders(buffered, response.Meta, response.Links)
+		}
+	}
+
+	renderCharacterBody(buffered, r, format, response, characters, fields)
+	h.writeConditional(w, r, buffered, characters)
+}
+
+func renderCharacterBody(w http.ResponseWriter, r *http.Request, format string, response interface{}, characters []rick_and_morty.Character, fields fieldset) {
+	if format == formatJSON {
+		renderCharacterJSON(w, r, response, fields)
+		return
+	}
+
+	var (
+		body        []byte
+		contentType string
+		err         error
+	)
+
+	switch format {
+	case formatNDJSON:
+		body, err = encodeNDJSON(characters, fields)
+		contentType = contentTypeNDJSON
+	case formatCSV:
+		body, err = encodeCSV(characters, fields)
+		contentType = contentTypeCSV
+	case formatXML:
+		body, err = encodeEnvelope(response, fields, encodeXML)
+		contentType = contentTypeXML
+	case formatMsgPack:
+		body, err = encodeEnvelope(response, fields, encodeMsgPack)
+		contentType = contentTypeMsgPack
+	default:
+		err = fmt.Errorf("unknown response format %q", format)
+	}
+
+	if err != nil {
+		log.Println(err)
+		utilities.RenderError(w, r, utilities.CodeInternal, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(body)
+}
+
+func setPageHeaders(w http.ResponseWriter, meta *PageMeta, links *PageLinks) {
+	if meta != nil {
+		w.Header().Set("X-Total-Count", strconv.Itoa(meta.Count))
+	}
+
+	if links == nil {
+		return
+	}
+
+	var parts []string
+	for _, link := range []struct{ rel, href string }{
+		{"self", links.Self},
+		{"first", links.First},
+		{"prev", links.Prev},
+		{"next", links.Next},
+		{"last", links.Last},
+	} {
+		if link.href != "" {
+			parts = append(parts, fmt.Sprintf(`<%s>; rel="%s"`, link.href, link.rel))
+		}
+	}
+
+	if len(parts) > 0 {
+		w.Header().Set("Link", strings.Join(parts, ", "))
+	}
+}
+
+func encodeNDJSON(characters []rick_and_morty.Character, fields fieldset) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	for _, character := range characters {
+		value, err := streamedCharacter(character, fields)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := writeStreamedValue(buf, value, formatNDJSON, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// encodeCSV writes one row per character, with a column for every selected
+// scalar field. Nested objects are flattened into columns such as
+// origin_name, and the episode list is joined with spaces.
+func encodeCSV(characters []rick_and_morty.Character, fields fieldset) ([]byte, error) {
+	columns := csvColumns(fields)
+
+	buf := &bytes.Buffer{}
+	writer := csv.NewWriter(buf)
+
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = strings.ReplaceAll(column, ".", "_")
+	}
+
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, character := range characters {
+		body, err := json.Marshal(character)
+		if err != nil {
+			return nil, err
+		}
+
+		var value interface{}
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = csvCell(lookupPath(value, strings.Split(column, ".")))
+		}
+
+		if err := writer.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// csvColumns lists the leaf paths of characterFields that fields selects,
+// in struct order.
+func csvColumns(fields fieldset) []string {
+	var columns []string
+
+	for i, path := range characterFields {
+		if i+1 < len(characterFields) && strings.HasPrefix(characterFields[i+1], path+".") {
+			continue
+		}
+
+		if fields.selects(strings.Split(path, ".")) {
+			columns = append(columns, path)
+		}
+	}
+
+	return columns
+}
+
+func (f fieldset) selects(path []string) bool {
+	if f == nil {
+		return true
+	}
+
+	child, ok := f[path[0]]
+	if !ok {
+		return false
+	}
+
+	return child == nil || (len(path) > 1 && child.selects(path[1:]))
+}
+
+func lookupPath(value interface{}, path []string) interface{} {
+	for _, name := range path {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+
+	return value
+}
+
+func csvCell(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case []interface{}:
+		cells := make([]string, len(value))
+		for i, item := range value {
+			cells[i] = csvCell(item)
+		}
+		return strings.Join(cells, " ")
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// encodeEnvelope projects response to fields as renderCharacterJSON does, and
+// hands the result to encode with object members in their JSON order.
+func encodeEnvelope(response interface{}, fields fieldset, encode func(interface{}) ([]byte, error)) ([]byte, error) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope jsonObject
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, err
+	}
+
+	for i, member := range envelope {
+		if member.name != "data" || fields == nil {
+			continue
+		}
+
+		raw, err := json.Marshal(member.value)
+		if err != nil {
+			return nil, err
+		}
+
+		projected, err := fields.project(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		if envelope[i].value, err = decodeOrdered(projected); err != nil {
+			return nil, err
+		}
+	}
+
+	return encode(envelope)
+}
+
+// jsonObject is a decoded JSON object that, unlike a map, keeps its members
+// in document order. Values nested in it are jsonObject, []interface{},
+// string, json.Number, bool or nil.
+type jsonObject []jsonMember
+
+type jsonMember struct {
+	name  string
+	value interface{}
+}
+
+func (o *jsonObject) UnmarshalJSON(data []byte) error {
+	value, err := decodeOrdered(data)
+	if err != nil {
+		return err
+	}
+
+	object, ok := value.(jsonObject)
+	if !ok {
+		return fmt.Errorf("expected a JSON object")
+	}
+
+	*o = object
+	return nil
+}
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+
+	for i, member := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		name, err := json.Marshal(member.name)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(member.value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func decodeOrdered(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	return decodeOrderedValue(decoder)
+}
+
+func decodeOrderedValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		object := jsonObject{}
+
+		for decoder.More() {
+			name, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := decodeOrderedValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			object = append(object, jsonMember{name: name.(string), value: value})
+		}
+
+		_, err := decoder.Token()
+		return object, err
+	case json.Delim('['):
+		array := []interface{}{}
+
+		for decoder.More() {
+			value, err := decodeOrderedValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+
+			array = append(array, value)
+		}
+
+		_, err := decoder.Token()
+		return array, err
+	default:
+		return token, nil
+	}
+}
+
+// encodeXML renders an ordered JSON value as a <response> document. Members
+// become elements of the same name, null members are left out, and array
+// items are named by xmlItemNames.
+func encodeXML(value interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString(xml.Header)
+
+	encoder := xml.NewEncoder(buf)
+	if err := writeXMLElement(encoder, "response", value); err != nil {
+		return nil, err
+	}
+
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeXMLElement(encoder *xml.Encoder, name string, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	var err error
+
+	switch value := value.(type) {
+	case jsonObject:
+		for _, member := range value {
+			if err = writeXMLElement(encoder, member.name, member.value); err != nil {
+				break
+			}
+		}
+	case []interface{}:
+		itemName, ok := xmlItemNames[name]
+		if !ok {
+			itemName = "item"
+		}
+
+		for _, item := range value {
+			if err = writeXMLElement(encoder, itemName, item); err != nil {
+				break
+			}
+		}
+	default:
+		err = encoder.EncodeToken(xml.CharData(fmt.Sprint(value)))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return encoder.EncodeToken(start.End())
+}
@@ -0,0 +1,225 @@
+package rick_and_morty
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gojo/gateways/rick_and_morty"
+	mockGateway "gojo/gateways/rick_and_morty/mock_gateway"
+	"gojo/utilities"
+)
+
+func TestHandler_ResponseFormats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it renders CSV with flattened origin and location columns", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().ListCharacters(gomock.Any()).Return(testCollectionCharacters()[:3], nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/list", h.ListCharacters, "/characters/list?per_page=2&fields=id,name,origin,location.name,episode", http.Header{"Accept": {"text/csv"}})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, contentTypeCSV, rec.Header().Get("Content-Type"))
+		assert.Equal(t, "id,name,origin_name,origin_url,location_name,episode\n"+
+			"1,Rick Sanchez,Earth (C-137),,Citadel of Ricks,1 2 3\n"+
+			"2,Morty Smith,unknown,,Citadel of Ricks,1 2 3\n", rec.Body.String())
+		assert.Equal(t, "3", rec.Header().Get("X-Total-Count"))
+		assert.Equal(t, `</characters/list?fields=id%2Cname%2Corigin%2Clocation.name%2Cepisode&page=1&per_page=2>; rel="self", `+
+			`</characters/list?fields=id%2Cname%2Corigin%2Clocation.name%2Cepisode&page=1&per_page=2>; rel="first", `+
+			`</characters/list?fields=id%2Cname%2Corigin%2Clocation.name%2Cepisode&page=2&per_page=2>; rel="next", `+
+			`</characters/list?fields=id%2Cname%2Corigin%2Clocation.name%2Cepisode&page=2&per_page=2>; rel="last"`, rec.Header().Get("Link"))
+	})
+
+	t.Run("it lets the format parameter override the Accept header", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetCharacters(gomock.Any(), []int{1, 2}).Return(rick_and_morty.CharacterBatch{Characters: testCollectionCharacters()[:2]}, nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/get/{ids}", h.GetCharacters, "/characters/get/1,2?format=ndjson&fields=id,name", http.Header{"Accept": {"text/csv"}})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, contentTypeNDJSON, rec.Header().Get("Content-Type"))
+		assert.Equal(t, "{\"id\":1,\"name\":\"Rick Sanchez\"}\n{\"id\":2,\"name\":\"Morty Smith\"}\n", rec.Body.String())
+	})
+
+	t.Run("it renders XML, naming array items after their parent", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetCharacters(gomock.Any(), []int{1, 9}).Return(rick_and_morty.CharacterBatch{
+			Characters: testCollectionCharacters()[:1],
+			Missing:    []int{9},
+		}, nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/get/{ids}", h.GetCharacters, "/characters/get/1,9?fields=id,episode", http.Header{"Accept": {"text/csv;q=0.5, application/xml"}})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, contentTypeXML, rec.Header().Get("Content-Type"))
+		assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+			`<response><data><character><episode><url>1</url><url>2</url><url>3</url></episode><id>1</id></character></data>`+
+			`<missing><id>9</id></missing></response>`, rec.Body.String())
+	})
+
+	t.Run("it renders MessagePack", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetCharacter(gomock.Any(), 1).Return(testCollectionCharacters()[0], nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/{id}", h.GetCharacter, "/characters/1?fields=id,name", http.Header{"Accept": {"application/msgpack"}})
+
+		expected := []byte{0x81, 0xa4}
+		expected = append(expected, "data"...)
+		expected = append(expected, 0x82, 0xa2)
+		expected = append(expected, "id"...)
+		expected = append(expected, 0x01, 0xa4)
+		expected = append(expected, "name"...)
+		expected = append(expected, 0xac)
+		expected = append(expected, "Rick Sanchez"...)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, contentTypeMsgPack, rec.Header().Get("Content-Type"))
+		assert.Equal(t, expected, rec.Body.Bytes())
+	})
+
+	t.Run("it returns a 406 when no accepted type is available", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: mockGateway.NewMockGateway(ctrl),
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/{id}", h.GetCharacter, "/characters/1", http.Header{"Accept": {"image/png, application/json;q=0"}})
+
+		response := errorBody{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+		assert.Equal(t, utilities.CodeNotAcceptable, response.Code)
+	})
+
+	for target, expectedError := range map[string]string{
+		"/characters/list?format=yaml":               `invalid format parameter "yaml": must be one of json, ndjson, csv, xml, msgpack`,
+		"/characters/list?format=csv&include=origin": "invalid include parameter: expansion is not supported for csv output",
+		"/characters/list?format=xml&stream=true":    "invalid stream parameter: streaming is only available as json or ndjson",
+	} {
+		target, expectedError := target, expectedError
+
+		t.Run("it rejects "+target, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			h, err := NewHandler(&HandlerConfig{
+				ApiClient: mockGateway.NewMockGateway(ctrl),
+			})
+
+			if err != nil {
+				t.FailNow()
+			}
+
+			rec := serveTestRequest(t, "/characters/list", h.ListCharacters, target, nil)
+
+			response := errorBody{}
+
+			err = json.Unmarshal(rec.Body.Bytes(), &response)
+			if err != nil {
+				t.FailNow()
+			}
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, expectedError, response.Error)
+		})
+	}
+}
+
+func TestHandler_ParseAccept(t *testing.T) {
+	t.Parallel()
+
+	for accept, expectedFormat := range map[string]string{
+		"":                                formatJSON,
+		"*/*":                             formatJSON,
+		"application/problem+json":        formatJSON,
+		"text/*":                          formatCSV,
+		"application/*;q=0.2, text/xml":   formatXML,
+		"application/x-ndjson, */*;q=0.1": formatNDJSON,
+		"application/vnd.msgpack;q=0.9, */*;q=0.8": formatMsgPack,
+	} {
+		accept, expectedFormat := accept, expectedFormat
+
+		t.Run("it picks "+expectedFormat+" for "+accept, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequest("GET", "/characters/list", nil)
+			if err != nil {
+				t.FailNow()
+			}
+			req.Header.Set("Accept", accept)
+
+			format, err := responseFormat(req)
+
+			assert.Nil(t, err)
+			assert.Equal(t, expectedFormat, format)
+		})
+	}
+}
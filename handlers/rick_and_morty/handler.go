@@ -1,10 +1,12 @@
 package rick_and_morty
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
-	"regexp"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
@@ -14,11 +16,15 @@ import (
 )
 
 type HandlerConfig struct {
-	ApiClient rick_and_morty.Gateway
+	ApiClient    rick_and_morty.Gateway
+	MaxBatchIDs  int    // Most distinct ids one multi-get may ask for; defaults to 200.
+	CacheControl string // Sent with every cacheable character response; defaults to "public, max-age=60".
 }
 
 type handler struct {
-	apiClient rick_and_morty.Gateway
+	apiClient    rick_and_morty.Gateway
+	maxBatchIDs  int
+	cacheControl string
 }
 
 func NewHandler(cfg *HandlerConfig) (Handler, error) {
@@ -27,95 +33,380 @@ func NewHandler(cfg *HandlerConfig) (Handler, error) {
 		return nil, fmt.Errorf("missing config parameter")
 	case cfg.ApiClient == nil:
 		return nil, fmt.Errorf("missing ApiClient parameter")
+	case cfg.MaxBatchIDs < 0:
+		return nil, fmt.Errorf("invalid MaxBatchIDs parameter")
+	}
+
+	maxBatchIDs := cfg.MaxBatchIDs
+	if maxBatchIDs == 0 {
+		maxBatchIDs = defaultMaxBatchIDs
+	}
+
+	cacheControl := cfg.CacheControl
+	if cacheControl == "" {
+		cacheControl = defaultCacheControl
 	}
 
 	return &handler{
-		apiClient: cfg.ApiClient,
+		apiClient:    cfg.ApiClient,
+		maxBatchIDs:  maxBatchIDs,
+		cacheControl: cacheControl,
 	}, nil
 }
 
 func (h *handler) GetCharacter(w http.ResponseWriter, r *http.Request) {
-	characterID := chi.URLParam(r, "id")
+	characterIDParam := chi.URLParam(r, "id")
+
+	if characterIDParam == "" {
+		renderMissingParameter(w, r, "id")
+		return
+	}
+
+	characterID, err := parseID("id", characterIDParam)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	format, err := responseFormat(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	fields, err := parseFieldset(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	includes, err := parseIncludes(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	if err := formatConflict(format, includes); err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
 
-	if characterID == "" {
-		log.Println("no characterID parameter passed in!")
-		utilities.RenderHTTPError(w, r)
+	character, err := h.apiClient.GetCharacter(r.Context(), characterID)
+	if err != nil {
+		log.Println(err)
+		renderGatewayError(w, r, err)
 		return
 	}
 
-	character, err := h.apiClient.GetCharacter(characterID)
+	included, err := h.resolveIncludes(r.Context(), []rick_and_morty.Character{character}, includes)
 	if err != nil {
 		log.Println(err)
-		utilities.RenderServerError(w, r, err)
+		renderGatewayError(w, r, err)
 		return
 	}
 
 	response := CharacterResponse{
-		Data: character,
+		Data:     character,
+		Included: included,
 	}
 
-	render.JSON(w, r, response)
+	h.renderCharacters(w, r, format, response, fields)
 }
 
 func (h *handler) GetCharacters(w http.ResponseWriter, r *http.Request) {
-	characterIDs := chi.URLParam(r, "ids")
+	characterIDs, err := parseIDs("ids", chi.URLParam(r, "ids"), h.maxBatchIDs)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	format, err := responseFormat(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	options, err := parseCollectionOptions(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
 
-	characterList, err := h.apiClient.GetCharacters(characterIDs)
+	fields, err := parseFieldset(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	includes, err := parseIncludes(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	if err := formatConflict(format, includes); err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	batch, err := h.apiClient.GetCharacters(r.Context(), characterIDs)
+	if err != nil {
+		log.Println(err)
+		renderGatewayError(w, r, err)
+		return
+	}
+
+	characterList := options.apply(batch.Characters)
+
+	included, err := h.resolveIncludes(r.Context(), characterList, includes)
 	if err != nil {
 		log.Println(err)
-		utilities.RenderServerError(w, r, err)
+		renderGatewayError(w, r, err)
 		return
 	}
 
 	response := ListCharactersResponse{
-		Data: characterList,
+		Data:     characterList,
+		Missing:  batch.Missing,
+		Included: included,
 	}
 
-	render.JSON(w, r, response)
+	h.renderCharacters(w, r, format, response, fields)
 }
 
 func (h *handler) SearchCharacters(w http.ResponseWriter, r *http.Request) {
-	name := r.URL.Query().Get("name")
+	filter, err := characterFilter(r.URL.Query())
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	if filter == (rick_and_morty.CharacterFilter{}) {
+		renderMissingParameter(w, r, "name", "status", "species", "type", "gender")
+		return
+	}
+
+	format, err := responseFormat(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	stream, err := streamFormat(r, format)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	options, err := parseCollectionOptions(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	fields, err := parseFieldset(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	includes, err := parseIncludes(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	if err := formatConflict(format, includes); err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	if stream != "" {
+		if err := streamingConflict(options, includes); err != nil {
+			renderValidationError(w, r, err)
+			return
+		}
+
+		streamCharacters(w, r, h.apiClient.SearchCharacterPages(r.Context(), filter), stream, options.filter, fields)
+		return
+	}
+
+	pageReq, err := parsePageRequest(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
 
-	if name == "" {
-		log.Println("no name parameter passed in!")
-		utilities.RenderHTTPError(w, r)
+	characterList, err := h.apiClient.SearchCharacters(r.Context(), filter)
+	if err != nil {
+		log.Println(err)
+		renderGatewayError(w, r, err)
 		return
 	}
 
-	reg := regexp.MustCompile(`[^A-Za-z]`)
-	searchParameter := reg.ReplaceAllString(name, "")
-	if searchParameter == "" {
-		log.Println("invalid name parameter passed in!")
-		utilities.RenderHTTPError(w, r)
+	page, meta, links, err := paginate(r, options.apply(characterList), pageReq)
+	if err != nil {
+		renderValidationError(w, r, err)
 		return
 	}
 
-	characterList, err := h.apiClient.SearchCharacters(searchParameter)
+	included, err := h.resolveIncludes(r.Context(), page, includes)
 	if err != nil {
 		log.Println(err)
-		utilities.RenderServerError(w, r, err)
+		renderGatewayError(w, r, err)
 		return
 	}
 
 	response := ListCharactersResponse{
-		Data: characterList,
+		Data:     page,
+		Meta:     meta,
+		Links:    links,
+		Included: included,
 	}
 
-	render.JSON(w, r, response)
+	h.renderCharacters(w, r, format, response, fields)
 }
 
 func (h *handler) ListCharacters(w http.ResponseWriter, r *http.Request) {
-	characterList, err := h.apiClient.ListCharacters()
+	format, err := responseFormat(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	stream, err := streamFormat(r, format)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	options, err := parseCollectionOptions(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	fields, err := parseFieldset(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	includes, err := parseIncludes(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	if err := formatConflict(format, includes); err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	if stream != "" {
+		if err := streamingConflict(options, includes); err != nil {
+			renderValidationError(w, r, err)
+			return
+		}
+
+		streamCharacters(w, r, h.apiClient.ListCharacterPages(r.Context()), stream, options.filter, fields)
+		return
+	}
+
+	pageReq, err := parsePageRequest(r)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	characterList, err := h.apiClient.ListCharacters(r.Context())
+	if err != nil {
+		log.Println(err)
+		renderGatewayError(w, r, err)
+		return
+	}
+
+	page, meta, links, err := paginate(r, options.apply(characterList), pageReq)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	included, err := h.resolveIncludes(r.Context(), page, includes)
 	if err != nil {
 		log.Println(err)
-		utilities.RenderServerError(w, r, err)
+		renderGatewayError(w, r, err)
 		return
 	}
 
 	response := ListCharactersResponse{
-		Data: characterList,
+		Data:     page,
+		Meta:     meta,
+		Links:    links,
+		Included: included,
+	}
+
+	h.renderCharacters(w, r, format, response, fields)
+}
+
+// cacheStatsReporter is the part of rick_and_morty.CachedGateway that
+// HealthCheck reports on. The cached gateway is always the outermost wrapper,
+// so an ApiClient that caches implements it.
+type cacheStatsReporter interface {
+	Stats() rick_and_morty.CacheStats
+}
+
+func (h *handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
+	circuitState := h.apiClient.CircuitState()
+
+	status := healthStatusOK
+	if circuitState != rick_and_morty.CircuitClosed {
+		status = healthStatusDegraded
+	}
+
+	response := HealthResponse{
+		Data: HealthStatus{
+			Status:   status,
+			Upstream: circuitState,
+		},
+	}
+
+	if cache, ok := h.apiClient.(cacheStatsReporter); ok {
+		cacheStats := cache.Stats()
+		response.Data.Cache = &cacheStats
 	}
 
 	render.JSON(w, r, response)
 }
+
+// renderGatewayError maps the gateway's typed errors onto the matching error
+// code, falling back to an internal error for anything it does not recognise.
+func renderGatewayError(w http.ResponseWriter, r *http.Request, err error) {
+	var upstreamErr *rick_and_morty.UpstreamError
+	if errors.As(err, &upstreamErr) && upstreamErr.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(upstreamErr.RetryAfter.Seconds()))))
+	}
+
+	utilities.RenderError(w, r, gatewayErrorCode(err), err)
+}
+
+func gatewayErrorCode(err error) utilities.ErrorCode {
+	switch {
+	case errors.Is(err, rick_and_morty.ErrNotFound):
+		return utilities.CodeNotFound
+	case errors.Is(err, rick_and_morty.ErrBadRequest):
+		return utilities.CodeUpstreamRejected
+	case errors.Is(err, rick_and_morty.ErrRateLimited):
+		return utilities.CodeUpstreamRateLimited
+	case errors.Is(err, rick_and_morty.ErrCircuitOpen):
+		return utilities.CodeCircuitOpen
+	case errors.Is(err, rick_and_morty.ErrUpstreamTimeout):
+		return utilities.CodeUpstreamTimeout
+	case errors.Is(err, rick_and_morty.ErrUpstreamUnavailable):
+		return utilities.CodeUpstreamUnavailable
+	case errors.Is(err, rick_and_morty.ErrDecode):
+		return utilities.CodeUpstreamMalformed
+	default:
+		return utilities.CodeInternal
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"gojo/gateways/rick_and_morty"
 	mockGateway "gojo/gateways/rick_and_morty/mock_gateway"
+	"gojo/utilities"
 )
 
 const (
@@ -24,8 +26,31 @@ const (
 )
 
 type errorBody struct {
-	Status string `json:"status"`
-	Error  string `json:"error"`
+	Status string              `json:"status"`
+	Code   utilities.ErrorCode `json:"code"`
+	Error  string              `json:"error"`
+}
+
+// serveTestRequest routes a GET for target, carrying header, through
+// handlerFunc mounted at pattern.
+func serveTestRequest(t *testing.T, pattern string, handlerFunc http.HandlerFunc, target string, header http.Header) *httptest.ResponseRecorder {
+	router := chi.NewRouter()
+	router.Get(pattern, handlerFunc)
+
+	req, err := http.NewRequest("GET", target, nil)
+	if err != nil {
+		t.FailNow()
+	}
+
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	return rec
 }
 
 func TestHandler_NewHandler(t *testing.T) {
@@ -47,6 +72,19 @@ func TestHandler_NewHandler(t *testing.T) {
 		assert.EqualError(t, fmt.Errorf("missing ApiClient parameter"), err.Error())
 	})
 
+	t.Run("it returns an error when a negative MaxBatchIDs is passed in", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, err := NewHandler(&HandlerConfig{
+			ApiClient:   mockGateway.NewMockGateway(ctrl),
+			MaxBatchIDs: -1,
+		})
+
+		assert.EqualError(t, fmt.Errorf("invalid MaxBatchIDs parameter"), err.Error())
+	})
+
 	t.Run("it successfully returns a Handler", func(t *testing.T) {
 		t.Parallel()
 		ctrl := gomock.NewController(t)
@@ -70,7 +108,7 @@ func TestHandler_GetCharacter(t *testing.T) {
 
 		gatewayMock := mockGateway.NewMockGateway(ctrl)
 
-		gatewayMock.EXPECT().GetCharacter(testCharacterID).Return(rick_and_morty.Character{}, fmt.Errorf(testErrorText))
+		gatewayMock.EXPECT().GetCharacter(gomock.Any(), 1).Return(rick_and_morty.Character{}, fmt.Errorf(testErrorText))
 
 		h, err := NewHandler(&HandlerConfig{
 			ApiClient: gatewayMock,
@@ -130,7 +168,7 @@ func TestHandler_GetCharacter(t *testing.T) {
 
 		gatewayMock := mockGateway.NewMockGateway(ctrl)
 
-		gatewayMock.EXPECT().GetCharacter(testCharacterID).Return(expectedCharacter, nil)
+		gatewayMock.EXPECT().GetCharacter(gomock.Any(), 1).Return(expectedCharacter, nil)
 
 		h, err := NewHandler(&HandlerConfig{
 			ApiClient: gatewayMock,
@@ -183,7 +221,7 @@ func TestHandler_GetCharacters(t *testing.T) {
 
 		gatewayMock := mockGateway.NewMockGateway(ctrl)
 
-		gatewayMock.EXPECT().GetCharacters(testMultipleCharacterIDs).Return([]rick_and_morty.Character{}, fmt.Errorf(testErrorText))
+		gatewayMock.EXPECT().GetCharacters(gomock.Any(), []int{1, 2}).Return(rick_and_morty.CharacterBatch{}, fmt.Errorf(testErrorText))
 
 		h, err := NewHandler(&HandlerConfig{
 			ApiClient: gatewayMock,
@@ -257,7 +295,7 @@ func TestHandler_GetCharacters(t *testing.T) {
 
 		gatewayMock := mockGateway.NewMockGateway(ctrl)
 
-		gatewayMock.EXPECT().GetCharacters(testMultipleCharacterIDs).Return(expectedCharacters, nil)
+		gatewayMock.EXPECT().GetCharacters(gomock.Any(), []int{1, 2}).Return(rick_and_morty.CharacterBatch{Characters: expectedCharacters}, nil)
 
 		h, err := NewHandler(&HandlerConfig{
 			ApiClient: gatewayMock,
@@ -342,10 +380,11 @@ func TestHandler_SearchCharacters(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 		assert.Equal(t, http.StatusText(http.StatusBadRequest), response.Status)
-		assert.Equal(t, http.StatusText(http.StatusBadRequest), response.Error)
+		assert.Equal(t, utilities.CodeMissingParameter, response.Code)
+		assert.Equal(t, "missing name, status, species, type or gender parameter", response.Error)
 	})
 
-	t.Run("it returns an error when an invalid search parameter is passed in", func(t *testing.T) {
+	t.Run("it returns an error naming each invalid search parameter", func(t *testing.T) {
 		t.Parallel()
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -361,7 +400,7 @@ func TestHandler_SearchCharacters(t *testing.T) {
 		router := chi.NewRouter()
 		router.Get("/characters/search", h.SearchCharacters)
 
-		req, err := http.NewRequest("GET", "/characters/search?name=001", nil)
+		req, err := http.NewRequest("GET", "/characters/search?name=Rick&status=zombie&gender=robot", nil)
 		if err != nil {
 			t.FailNow()
 		}
@@ -384,7 +423,7 @@ func TestHandler_SearchCharacters(t *testing.T) {
 
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 		assert.Equal(t, http.StatusText(http.StatusBadRequest), response.Status)
-		assert.Equal(t, http.StatusText(http.StatusBadRequest), response.Error)
+		assert.Equal(t, `invalid status parameter "zombie": must be one of alive, dead, unknown; invalid gender parameter "robot": must be one of female, male, genderless, unknown`, response.Error)
 	})
 
 	t.Run("it returns an error when the API Client returns an error", func(t *testing.T) {
@@ -394,7 +433,7 @@ func TestHandler_SearchCharacters(t *testing.T) {
 
 		gatewayMock := mockGateway.NewMockGateway(ctrl)
 
-		gatewayMock.EXPECT().SearchCharacters(testSearchCharacterQuery).Return([]rick_and_morty.Character{}, fmt.Errorf(testErrorText))
+		gatewayMock.EXPECT().SearchCharacters(gomock.Any(), rick_and_morty.CharacterFilter{Name: testSearchCharacterQuery}).Return([]rick_and_morty.Character{}, fmt.Errorf(testErrorText))
 
 		h, err := NewHandler(&HandlerConfig{
 			ApiClient: gatewayMock,
@@ -453,7 +492,7 @@ func TestHandler_SearchCharacters(t *testing.T) {
 
 		gatewayMock := mockGateway.NewMockGateway(ctrl)
 
-		gatewayMock.EXPECT().SearchCharacters(testSearchCharacterQuery).Return([]rick_and_morty.Character{expectedCharacter}, nil)
+		gatewayMock.EXPECT().SearchCharacters(gomock.Any(), rick_and_morty.CharacterFilter{Name: testSearchCharacterQuery}).Return([]rick_and_morty.Character{expectedCharacter}, nil)
 
 		h, err := NewHandler(&HandlerConfig{
 			ApiClient: gatewayMock,
@@ -489,11 +528,55 @@ func TestHandler_SearchCharacters(t *testing.T) {
 
 		expectedResponse := ListCharactersResponse{
 			Data: []rick_and_morty.Character{expectedCharacter},
+			Meta: &PageMeta{Count: 1, Pages: 1, Page: 1, PerPage: defaultPerPage},
+			Links: &PageLinks{
+				Self:  "/characters/search?name=Rick&page=1&per_page=20",
+				First: "/characters/search?name=Rick&page=1&per_page=20",
+				Last:  "/characters/search?name=Rick&page=1&per_page=20",
+			},
 		}
 
 		assert.Equal(t, http.StatusOK, rec.Code)
 		assert.Equal(t, expectedResponse, response)
 	})
+
+	t.Run("it passes every filter to the API Client without stripping punctuation", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().SearchCharacters(gomock.Any(), rick_and_morty.CharacterFilter{
+			Name:    "Mr. Poopybutthole",
+			Status:  "alive",
+			Species: "Poopybutthole",
+			Type:    "",
+			Gender:  "male",
+		}).Return([]rick_and_morty.Character{}, nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		router := chi.NewRouter()
+		router.Get("/characters/search", h.SearchCharacters)
+
+		req, err := http.NewRequest("GET", "/characters/search?name=Mr.%20Poopybutthole&status=Alive&species=Poopybutthole&gender=MALE", nil)
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
 }
 
 func TestHandler_ListCharacters(t *testing.T) {
@@ -506,7 +589,7 @@ func TestHandler_ListCharacters(t *testing.T) {
 
 		gatewayMock := mockGateway.NewMockGateway(ctrl)
 
-		gatewayMock.EXPECT().ListCharacters().Return([]rick_and_morty.Character{}, fmt.Errorf(testErrorText))
+		gatewayMock.EXPECT().ListCharacters(gomock.Any()).Return([]rick_and_morty.Character{}, fmt.Errorf(testErrorText))
 
 		h, err := NewHandler(&HandlerConfig{
 			ApiClient: gatewayMock,
@@ -580,7 +663,7 @@ func TestHandler_ListCharacters(t *testing.T) {
 
 		gatewayMock := mockGateway.NewMockGateway(ctrl)
 
-		gatewayMock.EXPECT().ListCharacters().Return(expectedCharacters, nil)
+		gatewayMock.EXPECT().ListCharacters(gomock.Any()).Return(expectedCharacters, nil)
 
 		h, err := NewHandler(&HandlerConfig{
 			ApiClient: gatewayMock,
@@ -616,9 +699,654 @@ func TestHandler_ListCharacters(t *testing.T) {
 
 		expectedResponse := ListCharactersResponse{
 			Data: expectedCharacters,
+			Meta: &PageMeta{Count: 2, Pages: 1, Page: 1, PerPage: defaultPerPage},
+			Links: &PageLinks{
+				Self:  "/characters/list?page=1&per_page=20",
+				First: "/characters/list?page=1&per_page=20",
+				Last:  "/characters/list?page=1&per_page=20",
+			},
 		}
 
 		assert.Equal(t, http.StatusOK, rec.Code)
 		assert.Equal(t, expectedResponse, response)
 	})
 }
+
+func TestHandler_GatewayErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name               string
+		gatewayErr         error
+		expectedStatus     int
+		expectedCode       utilities.ErrorCode
+		expectedRetryAfter string
+	}{
+		{
+			name:           "it returns a 404 when the character does not exist",
+			gatewayErr:     &rick_and_morty.UpstreamError{Kind: rick_and_morty.ErrNotFound, StatusCode: 404},
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   utilities.CodeNotFound,
+		},
+		{
+			name:           "it returns a 400 when the upstream rejects the request",
+			gatewayErr:     &rick_and_morty.UpstreamError{Kind: rick_and_morty.ErrBadRequest, StatusCode: 400},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   utilities.CodeUpstreamRejected,
+		},
+		{
+			name:           "it returns a 429 when the upstream rate limits the request",
+			gatewayErr:     &rick_and_morty.UpstreamError{Kind: rick_and_morty.ErrRateLimited, StatusCode: 429},
+			expectedStatus: http.StatusTooManyRequests,
+			expectedCode:   utilities.CodeUpstreamRateLimited,
+		},
+		{
+			name:               "it passes the upstream Retry-After on to the client",
+			gatewayErr:         &rick_and_morty.UpstreamError{Kind: rick_and_morty.ErrRateLimited, StatusCode: 429, RetryAfter: 1500 * time.Millisecond},
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedCode:       utilities.CodeUpstreamRateLimited,
+			expectedRetryAfter: "2",
+		},
+		{
+			name:           "it returns a 502 when the upstream is unavailable",
+			gatewayErr:     &rick_and_morty.UpstreamError{Kind: rick_and_morty.ErrUpstreamUnavailable, StatusCode: 503},
+			expectedStatus: http.StatusBadGateway,
+			expectedCode:   utilities.CodeUpstreamUnavailable,
+		},
+		{
+			name:           "it returns a 502 when the upstream response cannot be decoded",
+			gatewayErr:     &rick_and_morty.UpstreamError{Kind: rick_and_morty.ErrDecode, Err: fmt.Errorf(testErrorText)},
+			expectedStatus: http.StatusBadGateway,
+			expectedCode:   utilities.CodeUpstreamMalformed,
+		},
+		{
+			name:               "it returns a 503 while the circuit breaker is open",
+			gatewayErr:         &rick_and_morty.UpstreamError{Kind: rick_and_morty.ErrCircuitOpen, RetryAfter: 30 * time.Second},
+			expectedStatus:     http.StatusServiceUnavailable,
+			expectedCode:       utilities.CodeCircuitOpen,
+			expectedRetryAfter: "30",
+		},
+		{
+			name:           "it returns a 504 when the upstream times out",
+			gatewayErr:     &rick_and_morty.UpstreamError{Kind: rick_and_morty.ErrUpstreamTimeout, Err: fmt.Errorf(testErrorText)},
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedCode:   utilities.CodeUpstreamTimeout,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+			gatewayMock.EXPECT().GetCharacter(gomock.Any(), 1).Return(rick_and_morty.Character{}, test.gatewayErr)
+
+			h, err := NewHandler(&HandlerConfig{
+				ApiClient: gatewayMock,
+			})
+
+			if err != nil {
+				t.FailNow()
+			}
+
+			router := chi.NewRouter()
+			router.Get("/characters/{id}", h.GetCharacter)
+
+			req, err := http.NewRequest("GET", fmt.Sprintf("/characters/%s", testCharacterID), nil)
+			if err != nil {
+				t.FailNow()
+			}
+
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			jsonFromRequest, err := io.ReadAll(rec.Body)
+			if err != nil {
+				t.FailNow()
+			}
+
+			response := errorBody{}
+
+			err = json.Unmarshal(jsonFromRequest, &response)
+			if err != nil {
+				t.FailNow()
+			}
+
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			assert.Equal(t, http.StatusText(test.expectedStatus), response.Status)
+			assert.Equal(t, test.expectedCode, response.Code)
+			assert.Equal(t, test.gatewayErr.Error(), response.Error)
+			assert.Equal(t, test.expectedRetryAfter, rec.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestHandler_ConditionalRequests(t *testing.T) {
+	t.Parallel()
+
+	character := testCollectionCharacters()[0]
+
+	// serve fetches character 1, sending headers with the request, and returns
+	// the recorded response.
+	serve := func(t *testing.T, cfg *HandlerConfig, headers map[string]string) *httptest.ResponseRecorder {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetCharacter(gomock.Any(), 1).Return(character, nil)
+
+		cfg.ApiClient = gatewayMock
+
+		h, err := NewHandler(cfg)
+		if err != nil {
+			t.FailNow()
+		}
+
+		router := chi.NewRouter()
+		router.Get("/characters/{id}", h.GetCharacter)
+
+		req, err := http.NewRequest("GET", fmt.Sprintf("/characters/%s", testCharacterID), nil)
+		if err != nil {
+			t.FailNow()
+		}
+
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	t.Run("it sends validators and the default Cache-Control", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serve(t, &HandlerConfig{}, nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Regexp(t, `^"[0-9a-f]{64}"$`, rec.Header().Get("ETag"))
+		assert.Equal(t, "Sat, 04 Nov 2017 18:48:46 GMT", rec.Header().Get("Last-Modified"))
+		assert.Equal(t, "public, max-age=60", rec.Header().Get("Cache-Control"))
+	})
+
+	t.Run("it sends the configured Cache-Control", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serve(t, &HandlerConfig{CacheControl: "private, no-cache"}, nil)
+
+		assert.Equal(t, "private, no-cache", rec.Header().Get("Cache-Control"))
+	})
+
+	t.Run("it gives the same payload the same ETag and other formats a different one", func(t *testing.T) {
+		t.Parallel()
+
+		first := serve(t, &HandlerConfig{}, nil)
+		second := serve(t, &HandlerConfig{}, nil)
+		csv := serve(t, &HandlerConfig{}, map[string]string{"Accept": "text/csv"})
+
+		assert.Equal(t, first.Header().Get("ETag"), second.Header().Get("ETag"))
+		assert.NotEqual(t, first.Header().Get("ETag"), csv.Header().Get("ETag"))
+	})
+
+	tests := []struct {
+		name           string
+		headers        func(etag string) map[string]string
+		expectedStatus int
+	}{
+		{
+			name: "it returns a 304 when If-None-Match holds the current ETag",
+			headers: func(etag string) map[string]string {
+				return map[string]string{"If-None-Match": `"stale", ` + etag}
+			},
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name: "it compares weak If-None-Match tags",
+			headers: func(etag string) map[string]string {
+				return map[string]string{"If-None-Match": "W/" + etag}
+			},
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name: "it returns the body when If-None-Match holds another ETag",
+			headers: func(etag string) map[string]string {
+				return map[string]string{"If-None-Match": `"stale"`}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "it returns a 304 when nothing changed since If-Modified-Since",
+			headers: func(etag string) map[string]string {
+				return map[string]string{"If-Modified-Since": "Sat, 04 Nov 2017 18:48:46 GMT"}
+			},
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name: "it returns the body when the character changed after If-Modified-Since",
+			headers: func(etag string) map[string]string {
+				return map[string]string{"If-Modified-Since": "Sat, 04 Nov 2017 18:48:45 GMT"}
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "it ignores If-Modified-Since when If-None-Match is present",
+			headers: func(etag string) map[string]string {
+				return map[string]string{"If-None-Match": `"stale"`, "If-Modified-Since": "Sat, 04 Nov 2017 18:48:46 GMT"}
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			etag := serve(t, &HandlerConfig{}, nil).Header().Get("ETag")
+
+			rec := serve(t, &HandlerConfig{}, test.headers(etag))
+
+			assert.Equal(t, test.expectedStatus, rec.Code)
+			assert.Equal(t, etag, rec.Header().Get("ETag"))
+
+			if test.expectedStatus == http.StatusNotModified {
+				assert.Empty(t, rec.Body.String())
+				assert.Empty(t, rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+
+	t.Run("it sends no validators with an error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetCharacter(gomock.Any(), 1).Return(rick_and_morty.Character{}, fmt.Errorf(testErrorText))
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		router := chi.NewRouter()
+		router.Get("/characters/{id}", h.GetCharacter)
+
+		req, err := http.NewRequest("GET", fmt.Sprintf("/characters/%s", testCharacterID), nil)
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Empty(t, rec.Header().Get("ETag"))
+	})
+}
+
+type fakeCharacterIterator struct {
+	pages [][]rick_and_morty.Character
+	err   error
+	index int
+}
+
+func (it *fakeCharacterIterator) Next() bool {
+	if it.index >= len(it.pages) {
+		return false
+	}
+	it.index++
+	return true
+}
+
+func (it *fakeCharacterIterator) Page() []rick_and_morty.Character {
+	return it.pages[it.index-1]
+}
+
+func (it *fakeCharacterIterator) Info() rick_and_morty.ApiInfo {
+	return rick_and_morty.ApiInfo{}
+}
+
+func (it *fakeCharacterIterator) Err() error {
+	if it.index < len(it.pages) {
+		return nil
+	}
+	return it.err
+}
+
+func TestHandler_StreamCharacters(t *testing.T) {
+	t.Parallel()
+
+	pages := [][]rick_and_morty.Character{
+		{{Id: 1, Name: "Rick Sanchez"}, {Id: 2, Name: "Morty Smith"}},
+		{{Id: 3, Name: "Summer Smith"}},
+	}
+
+	t.Run("it returns an error when an invalid stream parameter is passed in", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: mockGateway.NewMockGateway(ctrl),
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		router := chi.NewRouter()
+		router.Get("/characters/list", h.ListCharacters)
+
+		req, err := http.NewRequest("GET", "/characters/list?stream=xml", nil)
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		response := invalidParamsBody{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, []utilities.InvalidParam{
+			{Name: "stream", Value: "xml", Reason: "must be one of true, false, json, ndjson"},
+		}, response.InvalidParams)
+	})
+
+	t.Run("it streams the list as a JSON envelope", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().ListCharacterPages(gomock.Any()).Return(&fakeCharacterIterator{pages: pages})
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		router := chi.NewRouter()
+		router.Get("/characters/list", h.ListCharacters)
+
+		req, err := http.NewRequest("GET", "/characters/list?stream=true", nil)
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		response := ListCharactersResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		expectedResponse := ListCharactersResponse{
+			Data: append(append([]rick_and_morty.Character{}, pages[0]...), pages[1]...),
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.True(t, rec.Flushed)
+		assert.Equal(t, expectedResponse, response)
+	})
+
+	t.Run("it streams search results as NDJSON", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().SearchCharacterPages(gomock.Any(), rick_and_morty.CharacterFilter{Name: testSearchCharacterQuery}).Return(&fakeCharacterIterator{pages: pages})
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		router := chi.NewRouter()
+		router.Get("/characters/search", h.SearchCharacters)
+
+		req, err := http.NewRequest("GET", fmt.Sprintf("/characters/search?name=%s&stream=true", testSearchCharacterQuery), nil)
+		if err != nil {
+			t.FailNow()
+		}
+		req.Header.Set("Accept", "application/x-ndjson")
+
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+		assert.Len(t, lines, 3)
+
+		for i, line := range lines {
+			character := rick_and_morty.Character{}
+
+			err = json.Unmarshal([]byte(line), &character)
+			if err != nil {
+				t.FailNow()
+			}
+
+			assert.Equal(t, i+1, character.Id)
+		}
+	})
+
+	t.Run("it renders an error when the first page fails", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().ListCharacterPages(gomock.Any()).Return(&fakeCharacterIterator{err: fmt.Errorf(testErrorText)})
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		router := chi.NewRouter()
+		router.Get("/characters/list", h.ListCharacters)
+
+		req, err := http.NewRequest("GET", "/characters/list?stream=ndjson", nil)
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		response := errorBody{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, testErrorText, response.Error)
+	})
+
+	t.Run("it reports a mid-stream failure in the JSON envelope", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().ListCharacterPages(gomock.Any()).Return(&fakeCharacterIterator{pages: pages[:1], err: fmt.Errorf(testErrorText)})
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		router := chi.NewRouter()
+		router.Get("/characters/list", h.ListCharacters)
+
+		req, err := http.NewRequest("GET", "/characters/list?stream=json", nil)
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		response := struct {
+			Data  []rick_and_morty.Character `json:"data"`
+			Error string                     `json:"error"`
+		}{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, pages[0], response.Data)
+		assert.Equal(t, testErrorText, response.Error)
+	})
+}
+
+// cachingGatewayMock adds the cache statistics of a CachedGateway to a
+// MockGateway.
+type cachingGatewayMock struct {
+	*mockGateway.MockGateway
+	stats rick_and_morty.CacheStats
+}
+
+func (g cachingGatewayMock) Stats() rick_and_morty.CacheStats {
+	return g.stats
+}
+
+func TestHandler_HealthCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		circuitState   rick_and_morty.CircuitState
+		cacheStats     *rick_and_morty.CacheStats
+		expectedStatus string
+	}{
+		{
+			name:           "it reports ok while the circuit is closed",
+			circuitState:   rick_and_morty.CircuitClosed,
+			expectedStatus: "ok",
+		},
+		{
+			name:           "it reports degraded while the circuit is open",
+			circuitState:   rick_and_morty.CircuitOpen,
+			expectedStatus: "degraded",
+		},
+		{
+			name:           "it reports the cache statistics when the gateway caches",
+			circuitState:   rick_and_morty.CircuitClosed,
+			cacheStats:     &rick_and_morty.CacheStats{Hits: 3, Misses: 1, Evictions: 2, Entries: 1},
+			expectedStatus: "ok",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+			gatewayMock.EXPECT().CircuitState().Return(test.circuitState)
+
+			var apiClient rick_and_morty.Gateway = gatewayMock
+			if test.cacheStats != nil {
+				apiClient = cachingGatewayMock{MockGateway: gatewayMock, stats: *test.cacheStats}
+			}
+
+			h, err := NewHandler(&HandlerConfig{
+				ApiClient: apiClient,
+			})
+
+			if err != nil {
+				t.FailNow()
+			}
+
+			router := chi.NewRouter()
+			router.Get("/health", h.HealthCheck)
+
+			req, err := http.NewRequest("GET", "/health", nil)
+			if err != nil {
+				t.FailNow()
+			}
+
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			response := HealthResponse{}
+
+			err = json.Unmarshal(rec.Body.Bytes(), &response)
+			if err != nil {
+				t.FailNow()
+			}
+
+			expectedResponse := HealthResponse{
+				Data: HealthStatus{
+					Status:   test.expectedStatus,
+					Upstream: test.circuitState,
+					Cache:    test.cacheStats,
+				},
+			}
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, expectedResponse, response)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package rick_and_morty
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gojo/utilities"
+)
+
+const defaultMaxBatchIDs = 200
+
+// parseID parses a single resource id path parameter, which must be a
+// positive integer.
+func parseID(name, raw string) (int, error) {
+	id, reason := parsePositiveID(strings.TrimSpace(raw))
+	if reason != "" {
+		return 0, invalidParams(invalidParam(name, raw, reason))
+	}
+
+	return id, nil
+}
+
+// parseIDs parses a comma-separated list of positive integer ids and
+// inclusive ranges such as "1-20". Duplicates are dropped, keeping the first
+// occurrence, and more than maxIDs distinct ids are rejected as soon as they
+// are seen. Every invalid token before that point is reported, not just the
+// first.
+func parseIDs(name, raw string, maxIDs int) ([]int, error) {
+	var (
+		ids      []int
+		problems []utilities.InvalidParam
+		seen     = map[int]bool{}
+	)
+
+	for _, token := range strings.Split(raw, ",") {
+		first, last, reason := parseIDRange(strings.TrimSpace(token))
+		if reason != "" {
+			problems = append(problems, invalidParam(name, token, reason))
+			continue
+		}
+
+		// Checking the size up front keeps a range like "1-999999999" from
+		// being expanded before it is rejected.
+		if last-first >= maxIDs {
+			problems = append(problems, invalidParam(name, token, fmt.Sprintf("ranges may span at most %d ids", maxIDs)))
+			continue
+		}
+
+		// The loop stops on last itself rather than past it, so a range ending
+		// at the largest int cannot wrap around.
+		for id := first; ; id++ {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+
+			if len(ids) > maxIDs {
+				problems = append(problems, invalidParam(name, "", fmt.Sprintf("at most %d distinct ids may be requested at once", maxIDs)))
+				return nil, invalidParams(problems...)
+			}
+
+			if id == last {
+				break
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, invalidParams(problems...)
+	}
+
+	return ids, nil
+}
+
+func parseIDRange(token string) (int, int, string) {
+	start, end, isRange := strings.Cut(token, "-")
+	if !isRange {
+		id, reason := parsePositiveID(token)
+		return id, id, reason
+	}
+
+	first, reason := parsePositiveID(start)
+	if reason != "" {
+		return 0, 0, "range start " + reason
+	}
+
+	last, reason := parsePositiveID(end)
+	if reason != "" {
+		return 0, 0, "range end " + reason
+	}
+
+	if first > last {
+		return 0, 0, "range start must not be greater than its end"
+	}
+
+	return first, last, ""
+}
+
+// parsePositiveID returns the id in token, or a reason it is not one. Only
+// plain decimal digits are accepted, so signs, spaces and path fragments are
+// all rejected.
+func parsePositiveID(token string) (int, string) {
+	if token == "" {
+		return 0, "must not be empty"
+	}
+
+	for _, c := range token {
+		if c < '0' || c > '9' {
+			return 0, "must be a positive integer"
+		}
+	}
+
+	id, err := strconv.Atoi(token)
+	switch {
+	case err != nil:
+		return 0, "is too large"
+	case id == 0:
+		return 0, "must be a positive integer"
+	}
+
+	return id, ""
+}
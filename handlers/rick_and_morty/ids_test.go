@@ -0,0 +1,192 @@
+package rick_and_morty
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gojo/gateways/rick_and_morty"
+	mockGateway "gojo/gateways/rick_and_morty/mock_gateway"
+	"gojo/utilities"
+)
+
+type invalidParamsBody struct {
+	Status        string                   `json:"status"`
+	Error         string                   `json:"error"`
+	InvalidParams []utilities.InvalidParam `json:"invalid_params"`
+}
+
+func TestHandler_CharacterIDs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it expands ranges and drops duplicates in request order", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetCharacters(gomock.Any(), []int{7, 1, 2, 3, 4}).Return(rick_and_morty.CharacterBatch{Characters: []rick_and_morty.Character{}}, nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/get/{ids}", h.GetCharacters, "/characters/get/7,1-3,%202%20,3-4,7", nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("it reports the ids the upstream did not return", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetCharacters(gomock.Any(), []int{1, 900, 2}).Return(rick_and_morty.CharacterBatch{
+			Characters: []rick_and_morty.Character{{Id: 1}, {Id: 2}},
+			Missing:    []int{900},
+		}, nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/get/{ids}", h.GetCharacters, "/characters/get/1,900,2", nil)
+
+		response := ListCharactersResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, []int{1, 2}, characterIDs(response.Data))
+		assert.Equal(t, []int{900}, response.Missing)
+	})
+
+	t.Run("it lists every invalid token", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: mockGateway.NewMockGateway(ctrl),
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/get/{ids}", h.GetCharacters, "/characters/get/1,abc,0,,5-2,-3,2-x,99999999999999999999", nil)
+
+		response := invalidParamsBody{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, []utilities.InvalidParam{
+			{Name: "ids", Value: "abc", Reason: "must be a positive integer"},
+			{Name: "ids", Value: "0", Reason: "must be a positive integer"},
+			{Name: "ids", Reason: "must not be empty"},
+			{Name: "ids", Value: "5-2", Reason: "range start must not be greater than its end"},
+			{Name: "ids", Value: "-3", Reason: "range start must not be empty"},
+			{Name: "ids", Value: "2-x", Reason: "range end must be a positive integer"},
+			{Name: "ids", Value: "99999999999999999999", Reason: "is too large"},
+		}, response.InvalidParams)
+	})
+
+	t.Run("it rejects batches larger than MaxBatchIDs", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient:   mockGateway.NewMockGateway(ctrl),
+			MaxBatchIDs: 5,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		for target, expectedParam := range map[string]utilities.InvalidParam{
+			"/characters/get/1-3,4-6":     {Name: "ids", Reason: "at most 5 distinct ids may be requested at once"},
+			"/characters/get/1-999999999": {Name: "ids", Value: "1-999999999", Reason: "ranges may span at most 5 ids"},
+		} {
+			rec := serveTestRequest(t, "/characters/get/{ids}", h.GetCharacters, target, nil)
+
+			response := invalidParamsBody{}
+
+			err = json.Unmarshal(rec.Body.Bytes(), &response)
+			if err != nil {
+				t.FailNow()
+			}
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, []utilities.InvalidParam{expectedParam}, response.InvalidParams)
+		}
+	})
+
+	t.Run("it rejects a non-numeric single id", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: mockGateway.NewMockGateway(ctrl),
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/{id}", h.GetCharacter, "/characters/1-2", nil)
+
+		response := invalidParamsBody{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, `invalid id parameter "1-2": must be a positive integer`, response.Error)
+		assert.Equal(t, []utilities.InvalidParam{{Name: "id", Value: "1-2", Reason: "must be a positive integer"}}, response.InvalidParams)
+	})
+
+	t.Run("it expands a range ending at the largest id without wrapping", func(t *testing.T) {
+		t.Parallel()
+
+		ids, err := parseIDs("ids", fmt.Sprintf("%d-%d", math.MaxInt-1, math.MaxInt), defaultMaxBatchIDs)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []int{math.MaxInt - 1, math.MaxInt}, ids)
+	})
+
+	t.Run("it stops expanding once the batch cap is exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		ids, err := parseIDs("ids", "1-3,4-6,abc,1000000-1000004", 5)
+
+		assert.Nil(t, ids)
+		assert.EqualError(t, err, `invalid ids parameter: at most 5 distinct ids may be requested at once`)
+	})
+}
@@ -0,0 +1,135 @@
+package rick_and_morty
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gojo/gateways/rick_and_morty"
+	"gojo/utilities"
+)
+
+const (
+	includeEpisodes = "episodes"
+	includeOrigin   = "origin"
+	includeLocation = "location"
+)
+
+var (
+	includeOptions = []string{includeEpisodes, includeOrigin, includeLocation}
+
+	errIncludeWhileStreaming = invalidParams(invalidParam("include", "", "expansion is not supported when streaming"))
+)
+
+type includeSet struct {
+	episodes bool
+	origin   bool
+	location bool
+}
+
+// parseIncludes reads the comma-separated include query parameter, naming
+// every unknown relationship in the error.
+func parseIncludes(r *http.Request) (includeSet, error) {
+	raw := r.URL.Query().Get("include")
+	if raw == "" {
+		return includeSet{}, nil
+	}
+
+	var includes includeSet
+	var problems []utilities.InvalidParam
+
+	for _, token := range strings.Split(raw, ",") {
+		switch strings.TrimSpace(token) {
+		case "":
+		case includeEpisodes:
+			includes.episodes = true
+		case includeOrigin:
+			includes.origin = true
+		case includeLocation:
+			includes.location = true
+		default:
+			problems = append(problems, invalidEnumParameter("include", token, includeOptions))
+		}
+	}
+
+	if len(problems) > 0 {
+		return includeSet{}, invalidParams(problems...)
+	}
+
+	return includes, nil
+}
+
+func (i includeSet) any() bool {
+	return i.episodes || i.origin || i.location
+}
+
+// resolveIncludes expands the requested relationships of characters with one
+// multi-get per resource type, returning nil when nothing was requested.
+// Origins and locations share the Locations list since both are locations.
+func (h *handler) resolveIncludes(ctx context.Context, characters []rick_and_morty.Character, includes includeSet) (*Included, error) {
+	if !includes.any() {
+		return nil, nil
+	}
+
+	var episodeURLs, locationURLs []string
+	for _, character := range characters {
+		if includes.episodes {
+			episodeURLs = append(episodeURLs, character.Episode...)
+		}
+		if includes.origin {
+			locationURLs = append(locationURLs, character.Origin.Url)
+		}
+		if includes.location {
+			locationURLs = append(locationURLs, character.Location.Url)
+		}
+	}
+
+	included := &Included{}
+
+	if ids := resourceIDs(episodeURLs); len(ids) > 0 {
+		episodes, err := h.apiClient.GetEpisodes(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		included.Episodes = episodes
+	}
+
+	if ids := resourceIDs(locationURLs); len(ids) > 0 {
+		locations, err := h.apiClient.GetLocations(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		included.Locations = locations
+	}
+
+	return included, nil
+}
+
+// resourceIDs extracts the trailing numeric ids from upstream resource URLs,
+// deduplicated and in ascending order. Blank URLs, as upstream uses for an
+// unknown origin, are skipped.
+func resourceIDs(urls []string) []int {
+	seen := map[int]bool{}
+	var ids []int
+
+	for _, u := range urls {
+		if u == "" {
+			continue
+		}
+
+		id, err := strconv.Atoi(path.Base(u))
+		if err != nil || seen[id] {
+			continue
+		}
+
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	return ids
+}
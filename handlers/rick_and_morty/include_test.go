@@ -0,0 +1,181 @@
+package rick_and_morty
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gojo/gateways/rick_and_morty"
+	mockGateway "gojo/gateways/rick_and_morty/mock_gateway"
+)
+
+func TestHandler_Includes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it expands episodes, origins and locations in one call per resource", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		characters := testCollectionCharacters()[:2]
+		characters[0].Episode = []string{"https://rickandmortyapi.com/api/episode/2", "https://rickandmortyapi.com/api/episode/1"}
+		characters[1].Episode = []string{"https://rickandmortyapi.com/api/episode/1"}
+		characters[0].Origin.Url = "https://rickandmortyapi.com/api/location/1"
+		characters[0].Location.Url = "https://rickandmortyapi.com/api/location/3"
+		characters[1].Location.Url = "https://rickandmortyapi.com/api/location/3"
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetCharacters(gomock.Any(), []int{1, 2}).Return(rick_and_morty.CharacterBatch{Characters: characters}, nil)
+		gatewayMock.EXPECT().GetEpisodes(gomock.Any(), []int{1, 2}).Return(testEpisodes(), nil)
+		gatewayMock.EXPECT().GetLocations(gomock.Any(), []int{1, 3}).Return(testLocations(), nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/get/{ids}", h.GetCharacters, "/characters/get/1,2?include=episodes,origin,location", nil)
+
+		response := ListCharactersResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, characters, response.Data)
+		assert.Equal(t, &Included{Episodes: testEpisodes(), Locations: testLocations()}, response.Included)
+	})
+
+	t.Run("it expands a lone reference", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		character := testCollectionCharacters()[0]
+		character.Origin.Url = "https://rickandmortyapi.com/api/location/1"
+		character.Location.Url = "https://rickandmortyapi.com/api/location/3"
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetCharacter(gomock.Any(), 1).Return(character, nil)
+		gatewayMock.EXPECT().GetLocations(gomock.Any(), []int{1}).Return(testLocations()[:1], nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/{id}", h.GetCharacter, "/characters/1?include=origin", nil)
+
+		response := CharacterResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, &Included{Locations: testLocations()[:1]}, response.Included)
+	})
+
+	t.Run("it skips unknown origins without calling the upstream", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().ListCharacters(gomock.Any()).Return(testCollectionCharacters()[1:2], nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/list", h.ListCharacters, "/characters/list?include=origin", nil)
+
+		response := ListCharactersResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, &Included{}, response.Included)
+	})
+
+	t.Run("it returns the gateway error when an expansion fails", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		character := testCollectionCharacters()[0]
+		character.Episode = []string{"https://rickandmortyapi.com/api/episode/1"}
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetCharacter(gomock.Any(), 1).Return(character, nil)
+		gatewayMock.EXPECT().GetEpisodes(gomock.Any(), []int{1}).Return([]rick_and_morty.Episode{}, &rick_and_morty.UpstreamError{Kind: rick_and_morty.ErrUpstreamUnavailable, StatusCode: 503})
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/{id}", h.GetCharacter, "/characters/1?include=episodes", nil)
+
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+	})
+
+	for target, expectedError := range map[string]string{
+		"/characters/list?include=friends":         `invalid include parameter "friends": must be one of episodes, origin, location`,
+		"/characters/list?include=origin&stream=1": errIncludeWhileStreaming.Error(),
+	} {
+		target, expectedError := target, expectedError
+
+		t.Run(fmt.Sprintf("it returns an error for %s", target), func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			h, err := NewHandler(&HandlerConfig{
+				ApiClient: mockGateway.NewMockGateway(ctrl),
+			})
+
+			if err != nil {
+				t.FailNow()
+			}
+
+			rec := serveTestRequest(t, "/characters/list", h.ListCharacters, target, nil)
+
+			response := errorBody{}
+
+			err = json.Unmarshal(rec.Body.Bytes(), &response)
+			if err != nil {
+				t.FailNow()
+			}
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, expectedError, response.Error)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package rick_and_morty
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"gojo/gateways/rick_and_morty"
+)
+
+func (h *handler) GetLocation(w http.ResponseWriter, r *http.Request) {
+	locationIDParam := chi.URLParam(r, "id")
+
+	if locationIDParam == "" {
+		renderMissingParameter(w, r, "id")
+		return
+	}
+
+	locationID, err := parseID("id", locationIDParam)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	location, err := h.apiClient.GetLocation(r.Context(), locationID)
+	if err != nil {
+		log.Println(err)
+		renderGatewayError(w, r, err)
+		return
+	}
+
+	response := LocationResponse{
+		Data: location,
+	}
+
+	render.JSON(w, r, response)
+}
+
+func (h *handler) GetLocations(w http.ResponseWriter, r *http.Request) {
+	locationIDs, err := parseIDs("ids", chi.URLParam(r, "ids"), h.maxBatchIDs)
+	if err != nil {
+		renderValidationError(w, r, err)
+		return
+	}
+
+	locationList, err := h.apiClient.GetLocations(r.Context(), locationIDs)
+	if err != nil {
+		log.Println(err)
+		renderGatewayError(w, r, err)
+		return
+	}
+
+	response := ListLocationsResponse{
+		Data: locationList,
+	}
+
+	render.JSON(w, r, response)
+}
+
+func (h *handler) SearchLocations(w http.ResponseWriter, r *http.Request) {
+	filter := rick_and_morty.LocationFilter{
+		Name:      strings.TrimSpace(r.URL.Query().Get("name")),
+		Type:      strings.TrimSpace(r.URL.Query().Get("type")),
+		Dimension: strings.TrimSpace(r.URL.Query().Get("dimension")),
+	}
+
+	if filter == (rick_and_morty.LocationFilter{}) {
+		renderMissingParameter(w, r, "name", "type", "dimension")
+		return
+	}
+
+	locationList, err := h.apiClient.SearchLocations(r.Context(), filter)
+	if err != nil {
+		log.Println(err)
+		renderGatewayError(w, r, err)
+		return
+	}
+
+	response := ListLocationsResponse{
+		Data: locationList,
+	}
+
+	render.JSON(w, r, response)
+}
+
+func (h *handler) ListLocations(w http.ResponseWriter, r *http.Request) {
+	locationList, err := h.apiClient.ListLocations(r.Context())
+	if err != nil {
+		log.Println(err)
+		renderGatewayError(w, r, err)
+		return
+	}
+
+	response := ListLocationsResponse{
+		Data: locationList,
+	}
+
+	render.JSON(w, r, response)
+}
@@ -0,0 +1,254 @@
+package rick_and_morty
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gojo/gateways/rick_and_morty"
+	mockGateway "gojo/gateways/rick_and_morty/mock_gateway"
+)
+
+const (
+	testLocationID          = "1"
+	testMultipleLocationIDs = "1,3"
+)
+
+func testLocations() []rick_and_morty.Location {
+	earthTime, _ := time.Parse(time.RFC3339, "2017-11-10T12:42:04.162Z")
+	citadelTime, _ := time.Parse(time.RFC3339, "2017-11-10T13:08:13.191Z")
+
+	return []rick_and_morty.Location{
+		{
+			Id:        1,
+			Name:      "Earth (C-137)",
+			Type:      "Planet",
+			Dimension: "Dimension C-137",
+			Residents: []string{"https://rickandmortyapi.com/api/character/38"},
+			Url:       "https://rickandmortyapi.com/api/location/1",
+			Created:   earthTime,
+		},
+		{
+			Id:        3,
+			Name:      "Citadel of Ricks",
+			Type:      "Space station",
+			Dimension: "unknown",
+			Residents: []string{"https://rickandmortyapi.com/api/character/8"},
+			Url:       "https://rickandmortyapi.com/api/location/3",
+			Created:   citadelTime,
+		},
+	}
+}
+
+func TestHandler_GetLocation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns an error when the API Client returns an error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetLocation(gomock.Any(), 1).Return(rick_and_morty.Location{}, fmt.Errorf(testErrorText))
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/locations/{id}", h.GetLocation, fmt.Sprintf("/locations/%s", testLocationID), nil)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("it successfully returns a Location", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expectedLocation := testLocations()[0]
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetLocation(gomock.Any(), 1).Return(expectedLocation, nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/locations/{id}", h.GetLocation, fmt.Sprintf("/locations/%s", testLocationID), nil)
+
+		response := LocationResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, LocationResponse{Data: expectedLocation}, response)
+	})
+}
+
+func TestHandler_GetLocations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it successfully returns a list of locations", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expectedLocations := testLocations()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetLocations(gomock.Any(), []int{1, 3}).Return(expectedLocations, nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/locations/get/{ids}", h.GetLocations, fmt.Sprintf("/locations/get/%s", testMultipleLocationIDs), nil)
+
+		response := ListLocationsResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, ListLocationsResponse{Data: expectedLocations}, response)
+	})
+}
+
+func TestHandler_SearchLocations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns an error when no filter is passed in", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: mockGateway.NewMockGateway(ctrl),
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/locations/search", h.SearchLocations, "/locations/search?type=+", nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("it passes the type and dimension filters to the API Client", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expectedLocations := testLocations()[:1]
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().SearchLocations(gomock.Any(), rick_and_morty.LocationFilter{
+			Type:      "Planet",
+			Dimension: "Dimension C-137",
+		}).Return(expectedLocations, nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/locations/search", h.SearchLocations, "/locations/search?type=Planet&dimension=Dimension%20C-137", nil)
+
+		response := ListLocationsResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, ListLocationsResponse{Data: expectedLocations}, response)
+	})
+}
+
+func TestHandler_ListLocations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns an error when the API Client returns an error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().ListLocations(gomock.Any()).Return([]rick_and_morty.Location{}, fmt.Errorf(testErrorText))
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/locations/list", h.ListLocations, "/locations/list", nil)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("it successfully returns a list of locations", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expectedLocations := testLocations()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().ListLocations(gomock.Any()).Return(expectedLocations, nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/locations/list", h.ListLocations, "/locations/list", nil)
+
+		response := ListLocationsResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, ListLocationsResponse{Data: expectedLocations}, response)
+	})
+}
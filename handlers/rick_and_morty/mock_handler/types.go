@@ -58,6 +58,66 @@ func (mr *MockHandlerMockRecorder) GetCharacters(w, r interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCharacters", reflect.TypeOf((*MockHandler)(nil).GetCharacters), w, r)
 }
 
+// GetEpisode mocks base method.
+func (m *MockHandler) GetEpisode(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetEpisode", w, r)
+}
+
+// GetEpisode indicates an expected call of GetEpisode.
+func (mr *MockHandlerMockRecorder) GetEpisode(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEpisode", reflect.TypeOf((*MockHandler)(nil).GetEpisode), w, r)
+}
+
+// GetEpisodes mocks base method.
+func (m *MockHandler) GetEpisodes(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetEpisodes", w, r)
+}
+
+// GetEpisodes indicates an expected call of GetEpisodes.
+func (mr *MockHandlerMockRecorder) GetEpisodes(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEpisodes", reflect.TypeOf((*MockHandler)(nil).GetEpisodes), w, r)
+}
+
+// GetLocation mocks base method.
+func (m *MockHandler) GetLocation(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetLocation", w, r)
+}
+
+// GetLocation indicates an expected call of GetLocation.
+func (mr *MockHandlerMockRecorder) GetLocation(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocation", reflect.TypeOf((*MockHandler)(nil).GetLocation), w, r)
+}
+
+// GetLocations mocks base method.
+func (m *MockHandler) GetLocations(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetLocations", w, r)
+}
+
+// GetLocations indicates an expected call of GetLocations.
+func (mr *MockHandlerMockRecorder) GetLocations(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocations", reflect.TypeOf((*MockHandler)(nil).GetLocations), w, r)
+}
+
+// HealthCheck mocks base method.
+func (m *MockHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "HealthCheck", w, r)
+}
+
+// HealthCheck indicates an expected call of HealthCheck.
+func (mr *MockHandlerMockRecorder) HealthCheck(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthCheck", reflect.TypeOf((*MockHandler)(nil).HealthCheck), w, r)
+}
+
 // ListCharacters mocks base method.
 func (m *MockHandler) ListCharacters(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -70,6 +130,30 @@ func (mr *MockHandlerMockRecorder) ListCharacters(w, r interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCharacters", reflect.TypeOf((*MockHandler)(nil).ListCharacters), w, r)
 }
 
+// ListEpisodes mocks base method.
+func (m *MockHandler) ListEpisodes(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListEpisodes", w, r)
+}
+
+// ListEpisodes indicates an expected call of ListEpisodes.
+func (mr *MockHandlerMockRecorder) ListEpisodes(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEpisodes", reflect.TypeOf((*MockHandler)(nil).ListEpisodes), w, r)
+}
+
+// ListLocations mocks base method.
+func (m *MockHandler) ListLocations(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ListLocations", w, r)
+}
+
+// ListLocations indicates an expected call of ListLocations.
+func (mr *MockHandlerMockRecorder) ListLocations(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLocations", reflect.TypeOf((*MockHandler)(nil).ListLocations), w, r)
+}
+
 // SearchCharacters mocks base method.
 func (m *MockHandler) SearchCharacters(w http.ResponseWriter, r *http.Request) {
 	m.ctrl.T.Helper()
@@ -81,3 +165,27 @@ func (mr *MockHandlerMockRecorder) SearchCharacters(w, r interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCharacters", reflect.TypeOf((*MockHandler)(nil).SearchCharacters), w, r)
 }
+
+// SearchEpisodes mocks base method.
+func (m *MockHandler) SearchEpisodes(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SearchEpisodes", w, r)
+}
+
+// SearchEpisodes indicates an expected call of SearchEpisodes.
+func (mr *MockHandlerMockRecorder) SearchEpisodes(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchEpisodes", reflect.TypeOf((*MockHandler)(nil).SearchEpisodes), w, r)
+}
+
+// SearchLocations mocks base method.
+func (m *MockHandler) SearchLocations(w http.ResponseWriter, r *http.Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SearchLocations", w, r)
+}
+
+// SearchLocations indicates an expected call of SearchLocations.
+func (mr *MockHandlerMockRecorder) SearchLocations(w, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchLocations", reflect.TypeOf((*MockHandler)(nil).SearchLocations), w, r)
+}
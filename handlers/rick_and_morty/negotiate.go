@@ -0,0 +1,152 @@
+package rick_and_morty
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gojo/utilities"
+)
+
+const (
+	formatJSON    = "json"
+	formatNDJSON  = "ndjson"
+	formatCSV     = "csv"
+	formatXML     = "xml"
+	formatMsgPack = "msgpack"
+
+	contentTypeNDJSON  = "application/x-ndjson"
+	contentTypeCSV     = "text/csv; charset=utf-8"
+	contentTypeXML     = "application/xml; charset=utf-8"
+	contentTypeMsgPack = "application/msgpack"
+)
+
+// responseFormats lists the formats character responses can be rendered in,
+// most preferred first, with the media types that select each one.
+var responseFormats = []struct {
+	name       string
+	mediaTypes []string
+}{
+	{formatJSON, []string{"application/json"}},
+	{formatNDJSON, []string{contentTypeNDJSON, "application/ndjson"}},
+	{formatCSV, []string{"text/csv"}},
+	{formatXML, []string{"application/xml", "text/xml"}},
+	{formatMsgPack, []string{contentTypeMsgPack, "application/x-msgpack", "application/vnd.msgpack"}},
+}
+
+var (
+	formatOptions = []string{formatJSON, formatNDJSON, formatCSV, formatXML, formatMsgPack}
+
+	errNotAcceptable = errors.New("none of the accepted media types are available; use application/json, " +
+		"application/x-ndjson, text/csv, application/xml or application/msgpack")
+)
+
+// responseFormat picks the format of a character response: the format query
+// parameter when given, otherwise the best match for the Accept header.
+// Requests without an Accept header, or accepting anything, get JSON.
+func responseFormat(r *http.Request) (string, error) {
+	if format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format"))); format != "" {
+		if !oneOf(format, formatOptions) {
+			return "", invalidParams(invalidEnumParameter("format", format, formatOptions))
+		}
+
+		return format, nil
+	}
+
+	ranges := parseAccept(r.Header.Get("Accept"))
+	if len(ranges) == 0 {
+		return formatJSON, nil
+	}
+
+	best, bestQuality := "", 0.0
+	for _, format := range responseFormats {
+		for _, mediaType := range format.mediaTypes {
+			if quality := ranges.quality(mediaType); quality > bestQuality {
+				best, bestQuality = format.name, quality
+			}
+		}
+	}
+
+	if best == "" {
+		return "", errNotAcceptable
+	}
+
+	return best, nil
+}
+
+type mediaRange struct {
+	mediaType string
+	quality   float64
+}
+
+type mediaRanges []mediaRange
+
+// parseAccept reads the media ranges of an Accept header. The problem+json
+// range only affects how errors are rendered, so it is left out.
+func parseAccept(header string) mediaRanges {
+	var ranges mediaRanges
+
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaType == "" || mediaType == utilities.ContentTypeProblemJSON {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.TrimSpace(name) != "q" {
+				continue
+			}
+
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				quality = q
+			}
+		}
+
+		ranges = append(ranges, mediaRange{mediaType: mediaType, quality: quality})
+	}
+
+	return ranges
+}
+
+// quality is the weight the most specific matching range gives mediaType,
+// or 0 when no range matches it.
+func (m mediaRanges) quality(mediaType string) float64 {
+	typeRange := strings.SplitN(mediaType, "/", 2)[0] + "/*"
+
+	quality, specificity := 0.0, 0
+	for _, mr := range m {
+		var s int
+		switch mr.mediaType {
+		case mediaType:
+			s = 3
+		case typeRange:
+			s = 2
+		case "*/*":
+			s = 1
+		default:
+			continue
+		}
+
+		if s > specificity {
+			quality, specificity = mr.quality, s
+		}
+	}
+
+	return quality
+}
+
+// formatConflict reports the options that the chosen format cannot carry.
+// NDJSON and CSV hold nothing but the characters, so expansions have nowhere
+// to go.
+func formatConflict(format string, includes includeSet) error {
+	if includes.any() && (format == formatNDJSON || format == formatCSV) {
+		return invalidParams(invalidParam("include", "", "expansion is not supported for "+format+" output"))
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package rick_and_morty
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	defaultPerPage = 20
+	maxPerPage     = 100
+)
+
+type pageRequest struct {
+	page    int
+	perPage int
+}
+
+// parsePageRequest reads the page and per_page query parameters, defaulting
+// to the first page of defaultPerPage items.
+func parsePageRequest(r *http.Request) (pageRequest, error) {
+	query := r.URL.Query()
+
+	page, err := positiveIntParameter(query, "page", 1)
+	if err != nil {
+		return pageRequest{}, err
+	}
+
+	perPage, err := positiveIntParameter(query, "per_page", defaultPerPage)
+	if err != nil {
+		return pageRequest{}, err
+	}
+
+	if perPage > maxPerPage {
+		return pageRequest{}, invalidParams(invalidParam("per_page", query.Get("per_page"), fmt.Sprintf("must be at most %d", maxPerPage)))
+	}
+
+	return pageRequest{page: page, perPage: perPage}, nil
+}
+
+func positiveIntParameter(query url.Values, name string, fallback int) (int, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return fallback, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 {
+		return 0, invalidParams(invalidParam(name, raw, "must be a positive integer"))
+	}
+
+	return value, nil
+}
+
+// paginate slices out the requested page of items and describes it. Asking
+// for a page past the last one is an error, except that an empty collection
+// still has a (blank) first page.
+func paginate[T any](r *http.Request, items []T, req pageRequest) ([]T, *PageMeta, *PageLinks, error) {
+	count := len(items)
+	pages := (count + req.perPage - 1) / req.perPage
+
+	if req.page > pages && req.page > 1 {
+		return nil, nil, nil, invalidParams(invalidParam("page", r.URL.Query().Get("page"), fmt.Sprintf("there are only %d pages", pages)))
+	}
+
+	start := (req.page - 1) * req.perPage
+	end := start + req.perPage
+	if end > count {
+		end = count
+	}
+
+	meta := &PageMeta{
+		Count:   count,
+		Pages:   pages,
+		Page:    req.page,
+		PerPage: req.perPage,
+	}
+
+	lastPage := pages
+	if lastPage == 0 {
+		lastPage = 1
+	}
+
+	links := &PageLinks{
+		Self:  pageLink(r, req.page, req.perPage),
+		First: pageLink(r, 1, req.perPage),
+		Last:  pageLink(r, lastPage, req.perPage),
+	}
+
+	if req.page < pages {
+		links.Next = pageLink(r, req.page+1, req.perPage)
+	}
+
+	if req.page > 1 {
+		links.Prev = pageLink(r, req.page-1, req.perPage)
+	}
+
+	return items[start:end], meta, links, nil
+}
+
+// pageLink rewrites the request URL to point at page, keeping every other
+// query parameter so filters carry over between pages.
+func pageLink(r *http.Request, page, perPage int) string {
+	query := r.URL.Query()
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(perPage))
+
+	link := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+
+	return link.String()
+}
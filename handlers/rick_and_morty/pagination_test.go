@@ -0,0 +1,201 @@
+package rick_and_morty
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gojo/gateways/rick_and_morty"
+	mockGateway "gojo/gateways/rick_and_morty/mock_gateway"
+)
+
+func testCharacterRange(n int) []rick_and_morty.Character {
+	characters := make([]rick_and_morty.Character, n)
+	for i := range characters {
+		characters[i] = rick_and_morty.Character{Id: i + 1, Name: fmt.Sprintf("Character %d", i+1)}
+	}
+
+	return characters
+}
+
+func TestHandler_Pagination(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns the requested page with links to our own API", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		characters := testCharacterRange(5)
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().SearchCharacters(gomock.Any(), rick_and_morty.CharacterFilter{Status: "alive"}).Return(characters, nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/search", h.SearchCharacters, "/characters/search?status=alive&page=2&per_page=2", nil)
+
+		response := ListCharactersResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		expectedResponse := ListCharactersResponse{
+			Data: characters[2:4],
+			Meta: &PageMeta{Count: 5, Pages: 3, Page: 2, PerPage: 2},
+			Links: &PageLinks{
+				Self:  "/characters/search?page=2&per_page=2&status=alive",
+				First: "/characters/search?page=1&per_page=2&status=alive",
+				Last:  "/characters/search?page=3&per_page=2&status=alive",
+				Next:  "/characters/search?page=3&per_page=2&status=alive",
+				Prev:  "/characters/search?page=1&per_page=2&status=alive",
+			},
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, expectedResponse, response)
+	})
+
+	t.Run("it returns an empty first page when the search matches nothing", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().SearchCharacters(gomock.Any(), rick_and_morty.CharacterFilter{Name: "zzz"}).Return([]rick_and_morty.Character{}, nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/search", h.SearchCharacters, "/characters/search?name=zzz", nil)
+
+		response := ListCharactersResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, response.Data)
+		assert.Equal(t, 0, response.Meta.Count)
+		assert.Equal(t, 1, response.Meta.Page)
+	})
+
+	t.Run("it returns a short last page without a next link", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		characters := testCharacterRange(5)
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().ListCharacters(gomock.Any()).Return(characters, nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/list", h.ListCharacters, "/characters/list?page=3&per_page=2", nil)
+
+		response := ListCharactersResponse{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, characters[4:], response.Data)
+		assert.Equal(t, "", response.Links.Next)
+		assert.Equal(t, "/characters/list?page=2&per_page=2", response.Links.Prev)
+	})
+
+	t.Run("it returns an error when the page is past the last one", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().ListCharacters(gomock.Any()).Return(testCharacterRange(5), nil)
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/characters/list", h.ListCharacters, "/characters/list?page=4&per_page=2", nil)
+
+		response := errorBody{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, `invalid page parameter "4": there are only 3 pages`, response.Error)
+	})
+
+	for target, expectedError := range map[string]string{
+		"/characters/list?page=0":        `invalid page parameter "0": must be a positive integer`,
+		"/characters/list?page=two":      `invalid page parameter "two": must be a positive integer`,
+		"/characters/list?per_page=-1":   `invalid per_page parameter "-1": must be a positive integer`,
+		"/characters/list?per_page=1000": `invalid per_page parameter "1000": must be at most 100`,
+	} {
+		target, expectedError := target, expectedError
+
+		t.Run(fmt.Sprintf("it returns an error for %s", target), func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			h, err := NewHandler(&HandlerConfig{
+				ApiClient: mockGateway.NewMockGateway(ctrl),
+			})
+
+			if err != nil {
+				t.FailNow()
+			}
+
+			rec := serveTestRequest(t, "/characters/list", h.ListCharacters, target, nil)
+
+			response := errorBody{}
+
+			err = json.Unmarshal(rec.Body.Bytes(), &response)
+			if err != nil {
+				t.FailNow()
+			}
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, expectedError, response.Error)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package rick_and_morty
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gojo/gateways/rick_and_morty"
+	mockGateway "gojo/gateways/rick_and_morty/mock_gateway"
+	"gojo/utilities"
+)
+
+func TestHandler_ProblemDetails(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it renders a problem document when the client Accepts one", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: mockGateway.NewMockGateway(ctrl),
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		router := chi.NewRouter()
+		router.Get("/characters/get/{ids}", h.GetCharacters)
+
+		req, err := http.NewRequest("GET", "/characters/get/1,abc", nil)
+		if err != nil {
+			t.FailNow()
+		}
+		req.Header.Set("Accept", "application/problem+json, application/json")
+
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		response := utilities.Problem{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, utilities.ContentTypeProblemJSON, rec.Header().Get("Content-Type"))
+		assert.Equal(t, utilities.Problem{
+			Type:     "urn:gojo:problem:invalid-parameters",
+			Title:    "One or more parameters are invalid",
+			Status:   http.StatusBadRequest,
+			Detail:   `invalid ids parameter "abc": must be a positive integer`,
+			Instance: "/characters/get/1,abc",
+			Code:     utilities.CodeInvalidParameters,
+			InvalidParams: []utilities.InvalidParam{
+				{Name: "ids", Value: "abc", Reason: "must be a positive integer"},
+			},
+		}, response)
+	})
+
+	t.Run("it renders every error as a problem document behind the middleware", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		gatewayMock := mockGateway.NewMockGateway(ctrl)
+
+		gatewayMock.EXPECT().GetCharacter(gomock.Any(), 1).Return(rick_and_morty.Character{}, &rick_and_morty.UpstreamError{Kind: rick_and_morty.ErrNotFound, StatusCode: 404})
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: gatewayMock,
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		router := chi.NewRouter()
+		router.Use(middleware.RequestID)
+		router.Use(utilities.ProblemDetails)
+		router.Get("/characters/{id}", h.GetCharacter)
+
+		req, err := http.NewRequest("GET", "/characters/1", nil)
+		if err != nil {
+			t.FailNow()
+		}
+		req.Header.Set(middleware.RequestIDHeader, "req-42")
+
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		response := utilities.Problem{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, utilities.ContentTypeProblemJSON, rec.Header().Get("Content-Type"))
+		assert.Equal(t, "urn:gojo:problem:not-found", response.Type)
+		assert.Equal(t, http.StatusNotFound, response.Status)
+		assert.Equal(t, utilities.CodeNotFound, response.Code)
+		assert.Equal(t, "req-42", response.RequestID)
+	})
+
+	t.Run("it keeps the plain error shape, with its code, by default", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		h, err := NewHandler(&HandlerConfig{
+			ApiClient: mockGateway.NewMockGateway(ctrl),
+		})
+
+		if err != nil {
+			t.FailNow()
+		}
+
+		rec := serveTestRequest(t, "/locations/search", h.SearchLocations, "/locations/search", nil)
+
+		response := errorBody{}
+
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		if err != nil {
+			t.FailNow()
+		}
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, errorBody{
+			Status: http.StatusText(http.StatusBadRequest),
+			Code:   utilities.CodeMissingParameter,
+			Error:  "missing name, type or dimension parameter",
+		}, response)
+	})
+}
@@ -0,0 +1,147 @@
+package rick_and_morty
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"gojo/gateways/rick_and_morty"
+	"gojo/utilities"
+)
+
+var (
+	streamOptions = []string{"true", "false", formatJSON, formatNDJSON}
+
+	errStreamFormat = invalidParams(invalidParam("stream", "", "streaming is only available as json or ndjson"))
+)
+
+// streamFormat reads the stream query parameter. An empty or "false" value
+// disables streaming; "true" or "json" streams in format, the negotiated
+// response format, which must then be JSON or NDJSON; "ndjson" always
+// streams one character per line.
+func streamFormat(r *http.Request, format string) (string, error) {
+	stream := r.URL.Query().Get("stream")
+
+	switch stream {
+	case "", "false", "0":
+		return "", nil
+	case "true", "1", formatJSON:
+		if format != formatJSON && format != formatNDJSON {
+			return "", errStreamFormat
+		}
+		return format, nil
+	case formatNDJSON:
+		return formatNDJSON, nil
+	default:
+		return "", invalidParams(invalidEnumParameter("stream", stream, streamOptions))
+	}
+}
+
+// streamingConflict reports the options that need the whole collection, or
+// extra upstream calls, and so cannot be combined with streaming.
+func streamingConflict(options collectionOptions, includes includeSet) error {
+	switch {
+	case len(options.sort) > 0:
+		return errSortWhileStreaming
+	case includes.any():
+		return errIncludeWhileStreaming
+	}
+
+	return nil
+}
+
+// streamCharacters writes each page of it to the client as soon as it
+// arrives. Errors on the first page are rendered as usual; once the response
+// has started, a later failure is reported in-band as a trailing "error"
+// member (JSON) or line (NDJSON), since the status code is already sent.
+// Characters failing filter are skipped and the rest are trimmed to fields;
+// sorting needs the whole collection, so it is not available here.
+func streamCharacters(w http.ResponseWriter, r *http.Request, it rick_and_morty.CharacterIterator, format string, filter localFilter, fields fieldset) {
+	more := it.Next()
+	if !more && it.Err() != nil {
+		log.Println(it.Err())
+		renderGatewayError(w, r, it.Err())
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+
+	if format == formatNDJSON {
+		w.Header().Set("Content-Type", contentTypeNDJSON)
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"data":[`)
+	}
+
+	written := 0
+	for ; more; more = it.Next() {
+		for _, character := range it.Page() {
+			if !filter.match(character) {
+				continue
+			}
+
+			value, err := streamedCharacter(character, fields)
+			if err == nil {
+				err = writeStreamedValue(w, value, format, written)
+			}
+			if err != nil {
+				log.Println(err)
+				return
+			}
+			written++
+		}
+
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	streamErr := it.Err()
+	if streamErr != nil {
+		log.Println(streamErr)
+	}
+
+	switch {
+	case format == formatNDJSON && streamErr != nil:
+		_ = writeStreamedValue(w, utilities.ErrorResponse{
+			StatusText: http.StatusText(http.StatusBadGateway),
+			ErrorText:  streamErr.Error(),
+		}, format, written)
+	case format == formatJSON && streamErr != nil:
+		errorText, _ := json.Marshal(streamErr.Error())
+		_, _ = io.WriteString(w, `],"error":`+string(errorText)+`}`)
+	case format == formatJSON:
+		_, _ = io.WriteString(w, `]}`)
+	}
+}
+
+func streamedCharacter(character rick_and_morty.Character, fields fieldset) (interface{}, error) {
+	if fields == nil {
+		return character, nil
+	}
+
+	body, err := json.Marshal(character)
+	if err != nil {
+		return nil, err
+	}
+
+	return fields.project(body)
+}
+
+func writeStreamedValue(w io.Writer, v interface{}, format string, index int) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case format == formatNDJSON:
+		body = append(body, '\n')
+	case index > 0:
+		body = append([]byte{','}, body...)
+	}
+
+	_, err = w.Write(body)
+	return err
+}
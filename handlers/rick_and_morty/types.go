@@ -11,12 +11,84 @@ type Handler interface {
 	GetCharacters(w http.ResponseWriter, r *http.Request)
 	SearchCharacters(w http.ResponseWriter, r *http.Request)
 	ListCharacters(w http.ResponseWriter, r *http.Request)
+	GetEpisode(w http.ResponseWriter, r *http.Request)
+	GetEpisodes(w http.ResponseWriter, r *http.Request)
+	SearchEpisodes(w http.ResponseWriter, r *http.Request)
+	ListEpisodes(w http.ResponseWriter, r *http.Request)
+	GetLocation(w http.ResponseWriter, r *http.Request)
+	GetLocations(w http.ResponseWriter, r *http.Request)
+	SearchLocations(w http.ResponseWriter, r *http.Request)
+	ListLocations(w http.ResponseWriter, r *http.Request)
+	HealthCheck(w http.ResponseWriter, r *http.Request)
 }
 
 type CharacterResponse struct {
-	Data rick_and_morty.Character `json:"data,omitempty"`
+	Data     rick_and_morty.Character `json:"data,omitempty"`
+	Included *Included                `json:"included,omitempty"`
 }
 
+// ListCharactersResponse carries Meta and Links only on the paginated list
+// and search endpoints, and Missing only on multi-get, listing requested ids
+// that do not exist. Included is only set when the request asks for it.
 type ListCharactersResponse struct {
-	Data []rick_and_morty.Character `json:"data,omitempty"`
+	Data     []rick_and_morty.Character `json:"data,omitempty"`
+	Meta     *PageMeta                  `json:"meta,omitempty"`
+	Links    *PageLinks                 `json:"links,omitempty"`
+	Missing  []int                      `json:"missing,omitempty"`
+	Included *Included                  `json:"included,omitempty"`
+}
+
+// Included carries the relationships expanded through include=, each listed
+// once however many characters refer to it.
+type Included struct {
+	Episodes  []rick_and_morty.Episode  `json:"episodes,omitempty"`
+	Locations []rick_and_morty.Location `json:"locations,omitempty"`
+}
+
+type PageMeta struct {
+	Count   int `json:"count"`
+	Pages   int `json:"pages"`
+	Page    int `json:"page"`
+	PerPage int `json:"per_page"`
+}
+
+// PageLinks point back at our own API, preserving the request's other query
+// parameters. Next and Prev are omitted on the last and first pages.
+type PageLinks struct {
+	Self  string `json:"self"`
+	First string `json:"first"`
+	Last  string `json:"last"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+}
+
+type EpisodeResponse struct {
+	Data rick_and_morty.Episode `json:"data,omitempty"`
+}
+
+type ListEpisodesResponse struct {
+	Data []rick_and_morty.Episode `json:"data,omitempty"`
+}
+
+type LocationResponse struct {
+	Data rick_and_morty.Location `json:"data,omitempty"`
+}
+
+type ListLocationsResponse struct {
+	Data []rick_and_morty.Location `json:"data,omitempty"`
+}
+
+const (
+	healthStatusOK       = "ok"
+	healthStatusDegraded = "degraded"
+)
+
+type HealthResponse struct {
+	Data HealthStatus `json:"data"`
+}
+
+type HealthStatus struct {
+	Status   string                      `json:"status"`
+	Upstream rick_and_morty.CircuitState `json:"upstream"`
+	Cache    *rick_and_morty.CacheStats  `json:"cache,omitempty"`
 }
@@ -0,0 +1,89 @@
+package rick_and_morty
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"gojo/utilities"
+)
+
+// invalidParamsError collects every rejected query or path parameter of a
+// request, so the client can fix them all in one go.
+type invalidParamsError struct {
+	params []utilities.InvalidParam
+}
+
+func (e *invalidParamsError) Error() string {
+	messages := make([]string, len(e.params))
+	for i, param := range e.params {
+		if param.Value == "" {
+			messages[i] = fmt.Sprintf("invalid %s parameter: %s", param.Name, param.Reason)
+		} else {
+			messages[i] = fmt.Sprintf("invalid %s parameter %q: %s", param.Name, param.Value, param.Reason)
+		}
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func invalidParams(params ...utilities.InvalidParam) *invalidParamsError {
+	return &invalidParamsError{params: params}
+}
+
+func invalidParam(name, value, reason string) utilities.InvalidParam {
+	return utilities.InvalidParam{Name: name, Value: value, Reason: reason}
+}
+
+func invalidEnumParameter(name, value string, allowed []string) utilities.InvalidParam {
+	return invalidParam(name, value, "must be one of "+strings.Join(allowed, ", "))
+}
+
+// renderValidationError renders a 400, listing the rejected parameters when
+// err carries them, or a 406 when no acceptable format is available.
+func renderValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Println(err)
+
+	var paramsErr *invalidParamsError
+	if errors.As(err, &paramsErr) {
+		utilities.RenderInvalidParamsError(w, r, err, paramsErr.params)
+		return
+	}
+
+	if errors.Is(err, errNotAcceptable) {
+		utilities.RenderError(w, r, utilities.CodeNotAcceptable, err)
+		return
+	}
+
+	utilities.RenderError(w, r, utilities.CodeBadRequest, err)
+}
+
+// renderMissingParameter renders a 400 for a request that lacks a required
+// parameter, or all of a set of parameters at least one of which is needed.
+func renderMissingParameter(w http.ResponseWriter, r *http.Request, names ...string) {
+	err := fmt.Errorf("missing %s parameter", joinOr(names))
+	log.Println(err)
+
+	utilities.RenderError(w, r, utilities.CodeMissingParameter, err)
+}
+
+// joinOr lists names as "a, b or c".
+func joinOr(names []string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
+func oneOf(value string, allowed []string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,19 +1,48 @@
 package main
 
 import (
-	"github.com/go-chi/chi/v5"
+	"context"
+	"errors"
+	"flag"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/go-chi/chi/v5"
 
+	"gojo/config"
 	"gojo/router"
 )
 
 func main() {
-	apiRouter, err := router.NewApiRouter(&router.ApiRouterConfig{
-		Handler: chi.NewRouter(),
-	})
+	cfg, err := config.Load(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.SetOutput(cfg.LogOutput())
+
+	apiRouter, err := router.NewApiRouter(cfg.ApiRouterConfig(chi.NewRouter()))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = apiRouter.Init()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err = apiRouter.Serve(ctx)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	apiRouter.Init()
+	log.Println("server stopped")
 }
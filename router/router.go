@@ -2,26 +2,61 @@ package router
 
 import (
 	"compress/flate"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
-	"os"
+	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
-	"github.com/go-chi/render"
 
 	rmGateway "gojo/gateways/rick_and_morty"
 	rmHandler "gojo/handlers/rick_and_morty"
+	"gojo/utilities"
 )
 
+const defaultShutdownGracePeriod = 15 * time.Second
+
 type ApiRouterConfig struct {
-	Handler chi.Router
+	Handler          chi.Router
+	Addr             string                   // Address Serve listens on, such as ":8080".
+	AllowedOrigins   []string                 // CORS origins; defaults to http://localhost on the listening port. "*" allows any origin, without credentials.
+	Gateway          *rmGateway.GatewayConfig // Defaults to a GatewayConfig with every setting at its default.
+	Cache            *rmGateway.CacheConfig   // Caches upstream responses when set; its Gateway field is filled in by Init.
+	CoalesceRequests bool                     // Shares one upstream call between concurrent identical requests.
+	ProblemDetails   bool                     // Renders every error as application/problem+json, not only when Accepted.
+	MaxBatchIDs      int                      // Most distinct ids one multi-get may ask for; defaults to 200.
+	CacheControl     string                   // Sent with cacheable character responses; defaults to "public, max-age=60".
+	QuietRequests    bool                     // Skips the per-request access log.
+	LogOutput        io.Writer                // Where the access log goes; defaults to chi's logger on stdout.
+
+	ShutdownGracePeriod time.Duration // How long Serve lets in-flight requests finish once its context ends; defaults to 15 seconds.
 }
 
 type ApiRouter struct {
-	handler chi.Router
+	handler             chi.Router
+	addr                string
+	allowedOrigins      []string
+	gatewayConfig       *rmGateway.GatewayConfig
+	cacheConfig         *rmGateway.CacheConfig
+	coalesceRequests    bool
+	problemDetails      bool
+	maxBatchIDs         int
+	cacheControl        string
+	quietRequests       bool
+	logOutput           io.Writer
+	shutdownGracePeriod time.Duration
+
+	mu       sync.Mutex
+	gateway  rmGateway.Gateway
+	server   *http.Server
+	shutdown bool
 }
 
 func NewApiRouter(cfg *ApiRouterConfig) (*ApiRouter, error) {
@@ -30,47 +65,231 @@ func NewApiRouter(cfg *ApiRouterConfig) (*ApiRouter, error) {
 		return nil, fmt.Errorf("missing config parameter")
 	case cfg.Handler == nil:
 		return nil, fmt.Errorf("missing Handler parameter")
+	case cfg.Addr == "":
+		return nil, fmt.Errorf("missing Addr parameter")
+	case cfg.ShutdownGracePeriod < 0:
+		return nil, fmt.Errorf("invalid ShutdownGracePeriod parameter")
+	}
+
+	allowedOrigins := cfg.AllowedOrigins
+	if len(allowedOrigins) == 0 {
+		_, port, _ := net.SplitHostPort(cfg.Addr)
+		allowedOrigins = []string{fmt.Sprintf("http://localhost:%s", port)}
+	}
+
+	gatewayConfig := cfg.Gateway
+	if gatewayConfig == nil {
+		gatewayConfig = &rmGateway.GatewayConfig{}
+	}
+
+	shutdownGracePeriod := cfg.ShutdownGracePeriod
+	if shutdownGracePeriod == 0 {
+		shutdownGracePeriod = defaultShutdownGracePeriod
 	}
 
 	return &ApiRouter{
-		handler: cfg.Handler,
+		handler:             cfg.Handler,
+		addr:                cfg.Addr,
+		allowedOrigins:      allowedOrigins,
+		gatewayConfig:       gatewayConfig,
+		cacheConfig:         cfg.Cache,
+		coalesceRequests:    cfg.CoalesceRequests,
+		problemDetails:      cfg.ProblemDetails,
+		maxBatchIDs:         cfg.MaxBatchIDs,
+		cacheControl:        cfg.CacheControl,
+		quietRequests:       cfg.QuietRequests,
+		logOutput:           cfg.LogOutput,
+		shutdownGracePeriod: shutdownGracePeriod,
 	}, nil
 }
 
-func (r *ApiRouter) Init() {
-	port := os.Getenv("PORT")
-
-	r.handler.Use(middleware.Logger)
+// Init builds the gateway and handler and mounts every route on the
+// configured chi.Router, ready for Serve or for use as an http.Handler.
+func (r *ApiRouter) Init() error {
+	r.handler.Use(middleware.RequestID)
+	switch {
+	case r.quietRequests:
+	case r.logOutput != nil:
+		r.handler.Use(middleware.RequestLogger(&middleware.DefaultLogFormatter{
+			Logger:  log.New(r.logOutput, "", log.LstdFlags),
+			NoColor: true,
+		}))
+	default:
+		r.handler.Use(middleware.Logger)
+	}
 	r.handler.Use(middleware.Recoverer)
 	r.handler.Use(middleware.Compress(flate.DefaultCompression))
 	r.handler.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{fmt.Sprintf("http://localhost:%s", port)},
+		AllowedOrigins:   r.allowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Content-Type", "Authorization", "Accept"},
-		AllowCredentials: true,
+		AllowCredentials: !allowsAnyOrigin(r.allowedOrigins),
 	}))
 
-	r.handler.Use(render.SetContentType(render.ContentTypeJSON))
+	if r.problemDetails {
+		r.handler.Use(utilities.ProblemDetails)
+	}
 
-	rickAndMortyGateway, err := rmGateway.NewGateway(&rmGateway.GatewayConfig{})
+	rickAndMortyGateway, err := r.newGateway()
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	rickAndMortyHandler, err := rmHandler.NewHandler(&rmHandler.HandlerConfig{
-		ApiClient: rickAndMortyGateway,
+		ApiClient:    rickAndMortyGateway,
+		MaxBatchIDs:  r.maxBatchIDs,
+		CacheControl: r.cacheControl,
 	})
 	if err != nil {
-		log.Fatal(err)
+		_ = rickAndMortyGateway.Close()
+		return err
 	}
 
+	r.mu.Lock()
+	r.gateway = rickAndMortyGateway
+	r.mu.Unlock()
+
 	r.handler.Get("/characters/{id}", rickAndMortyHandler.GetCharacter)
 	r.handler.Get("/characters/get/{ids}", rickAndMortyHandler.GetCharacters)
 	r.handler.Get("/characters/search", rickAndMortyHandler.SearchCharacters)
 	r.handler.Get("/characters/list", rickAndMortyHandler.ListCharacters)
+	r.handler.Get("/episodes/{id}", rickAndMortyHandler.GetEpisode)
+	r.handler.Get("/episodes/get/{ids}", rickAndMortyHandler.GetEpisodes)
+	r.handler.Get("/episodes/search", rickAndMortyHandler.SearchEpisodes)
+	r.handler.Get("/episodes/list", rickAndMortyHandler.ListEpisodes)
+	r.handler.Get("/locations/{id}", rickAndMortyHandler.GetLocation)
+	r.handler.Get("/locations/get/{ids}", rickAndMortyHandler.GetLocations)
+	r.handler.Get("/locations/search", rickAndMortyHandler.SearchLocations)
+	r.handler.Get("/locations/list", rickAndMortyHandler.ListLocations)
+	r.handler.Get("/health", rickAndMortyHandler.HealthCheck)
+
+	return nil
+}
 
-	err = http.ListenAndServe(":"+port, r.handler)
+// newGateway builds the base gateway and wraps it for coalescing and caching
+// as configured, closing what it built if a later step fails.
+func (r *ApiRouter) newGateway() (rmGateway.Gateway, error) {
+	rickAndMortyGateway, err := rmGateway.NewGateway(r.gatewayConfig)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+
+	if r.coalesceRequests {
+		coalescingGateway, err := rmGateway.NewCoalescingGateway(&rmGateway.CoalescingConfig{
+			Gateway: rickAndMortyGateway,
+		})
+		if err != nil {
+			_ = rickAndMortyGateway.Close()
+			return nil, err
+		}
+
+		rickAndMortyGateway = coalescingGateway
+	}
+
+	if r.cacheConfig != nil {
+		cacheConfig := *r.cacheConfig
+		cacheConfig.Gateway = rickAndMortyGateway
+
+		cachedGateway, err := rmGateway.NewCachedGateway(&cacheConfig)
+		if err != nil {
+			_ = rickAndMortyGateway.Close()
+			return nil, err
+		}
+
+		rickAndMortyGateway = cachedGateway
+	}
+
+	return rickAndMortyGateway, nil
+}
+
+// Serve listens on the configured address until ctx ends, then shuts down,
+// giving in-flight requests up to the grace period to finish. It returns nil
+// after a clean shutdown, the listener's error if it could not serve, and
+// http.ErrServerClosed without listening once Shutdown has been called.
+func (r *ApiRouter) Serve(ctx context.Context) error {
+	server := &http.Server{
+		Addr:    r.addr,
+		Handler: r.handler,
+	}
+
+	r.mu.Lock()
+	if r.shutdown {
+		r.mu.Unlock()
+		return http.ErrServerClosed
+	}
+	r.server = server
+	r.mu.Unlock()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+
+		_ = r.closeGateway()
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), r.shutdownGracePeriod)
+	defer cancel()
+
+	return r.Shutdown(shutdownCtx)
+}
+
+// Shutdown stops accepting connections, waits for in-flight requests until
+// ctx ends, and then closes the gateway. When ctx ends first, the remaining
+// connections are closed and ctx's error is returned.
+func (r *ApiRouter) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	r.shutdown = true
+	server := r.server
+	r.mu.Unlock()
+
+	var shutdownErr error
+	if server != nil {
+		shutdownErr = server.Shutdown(ctx)
+		if shutdownErr != nil {
+			_ = server.Close()
+		}
 	}
+
+	closeErr := r.closeGateway()
+
+	if shutdownErr != nil {
+		return shutdownErr
+	}
+
+	return closeErr
+}
+
+func (r *ApiRouter) closeGateway() error {
+	r.mu.Lock()
+	gateway := r.gateway
+	r.gateway = nil
+	r.mu.Unlock()
+
+	if gateway == nil {
+		return nil
+	}
+
+	return gateway.Close()
+}
+
+// allowsAnyOrigin reports whether origins holds the "*" wildcard. Browsers
+// reject a wildcard Access-Control-Allow-Origin on credentialed requests, so
+// credentials are only allowed for an explicit list of origins.
+func allowsAnyOrigin(origins []string) bool {
+	for _, origin := range origins {
+		if origin == "*" {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,406 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+
+	rmGateway "gojo/gateways/rick_and_morty"
+)
+
+// freeAddr returns a loopback address with a port nothing is listening on.
+func freeAddr(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.FailNow()
+	}
+	defer listener.Close()
+
+	return listener.Addr().String()
+}
+
+// waitForServer polls addr until it accepts connections.
+func waitForServer(t *testing.T, addr string) {
+	for i := 0; i < 100; i++ {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			conn.Close()
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.FailNow()
+}
+
+func TestApiRouter_NewApiRouter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns an error when no config passed in", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewApiRouter(nil)
+
+		assert.EqualError(t, fmt.Errorf("missing config parameter"), err.Error())
+	})
+
+	t.Run("it returns an error when no Handler passed in", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewApiRouter(&ApiRouterConfig{})
+
+		assert.EqualError(t, fmt.Errorf("missing Handler parameter"), err.Error())
+	})
+
+	t.Run("it returns an error when no Addr passed in", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewApiRouter(&ApiRouterConfig{Handler: chi.NewRouter()})
+
+		assert.EqualError(t, fmt.Errorf("missing Addr parameter"), err.Error())
+	})
+
+	t.Run("it returns an error when a negative ShutdownGracePeriod is passed in", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewApiRouter(&ApiRouterConfig{Handler: chi.NewRouter(), Addr: ":8080", ShutdownGracePeriod: -time.Second})
+
+		assert.EqualError(t, fmt.Errorf("invalid ShutdownGracePeriod parameter"), err.Error())
+	})
+}
+
+func TestApiRouter_Init(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns gateway construction errors", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := NewApiRouter(&ApiRouterConfig{
+			Handler: chi.NewRouter(),
+			Addr:    ":8080",
+			Gateway: &rmGateway.GatewayConfig{BaseURL: "localhost:8080"},
+		})
+		if err != nil {
+			t.FailNow()
+		}
+
+		err = r.Init()
+
+		assert.EqualError(t, fmt.Errorf("invalid BaseURL parameter"), err.Error())
+	})
+
+	t.Run("it returns cache construction errors", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := NewApiRouter(&ApiRouterConfig{
+			Handler: chi.NewRouter(),
+			Addr:    ":8080",
+			Cache:   &rmGateway.CacheConfig{MaxEntries: -1},
+		})
+		if err != nil {
+			t.FailNow()
+		}
+
+		err = r.Init()
+
+		assert.EqualError(t, fmt.Errorf("invalid MaxEntries parameter"), err.Error())
+	})
+
+	t.Run("it returns handler construction errors", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := NewApiRouter(&ApiRouterConfig{
+			Handler:     chi.NewRouter(),
+			Addr:        ":8080",
+			MaxBatchIDs: -1,
+		})
+		if err != nil {
+			t.FailNow()
+		}
+
+		err = r.Init()
+
+		assert.EqualError(t, fmt.Errorf("invalid MaxBatchIDs parameter"), err.Error())
+	})
+
+	t.Run("it mounts the routes without serving", func(t *testing.T) {
+		t.Parallel()
+
+		handler := chi.NewRouter()
+
+		r, err := NewApiRouter(&ApiRouterConfig{
+			Handler:          handler,
+			Addr:             ":8080",
+			Cache:            &rmGateway.CacheConfig{},
+			CoalesceRequests: true,
+		})
+		if err != nil {
+			t.FailNow()
+		}
+
+		err = r.Init()
+		assert.Nil(t, err)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"cache":{`)
+		assert.Nil(t, r.Shutdown(context.Background()))
+	})
+
+	t.Run("it allows any origin without credentials for a wildcard", func(t *testing.T) {
+		t.Parallel()
+
+		handler := chi.NewRouter()
+
+		r, err := NewApiRouter(&ApiRouterConfig{
+			Handler:        handler,
+			Addr:           ":8080",
+			AllowedOrigins: []string{"*"},
+		})
+		if err != nil {
+			t.FailNow()
+		}
+
+		err = r.Init()
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest("GET", "/health", nil)
+		req.Header.Set("Origin", "http://example.com")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Nil(t, r.Shutdown(context.Background()))
+	})
+
+	t.Run("it keeps the CORS Vary header on cacheable character responses", func(t *testing.T) {
+		t.Parallel()
+
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			_, _ = io.WriteString(w, `{"id": 1, "name": "Rick Sanchez"}`)
+		}))
+		defer upstream.Close()
+
+		handler := chi.NewRouter()
+
+		r, err := NewApiRouter(&ApiRouterConfig{
+			Handler:        handler,
+			Addr:           ":8080",
+			AllowedOrigins: []string{"http://one.example.com", "http://two.example.com"},
+			Gateway:        &rmGateway.GatewayConfig{BaseURL: upstream.URL},
+			CacheControl:   "public, max-age=5",
+		})
+		if err != nil {
+			t.FailNow()
+		}
+
+		err = r.Init()
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest("GET", "/characters/1", nil)
+		req.Header.Set("Origin", "http://one.example.com")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "http://one.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "public, max-age=5", rec.Header().Get("Cache-Control"))
+		assert.Subset(t, rec.Header().Values("Vary"), []string{"Origin", "Accept"})
+		assert.Nil(t, r.Shutdown(context.Background()))
+	})
+
+	t.Run("it allows credentials for listed origins", func(t *testing.T) {
+		t.Parallel()
+
+		handler := chi.NewRouter()
+
+		r, err := NewApiRouter(&ApiRouterConfig{
+			Handler:        handler,
+			Addr:           ":8080",
+			AllowedOrigins: []string{"http://example.com"},
+		})
+		if err != nil {
+			t.FailNow()
+		}
+
+		err = r.Init()
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest("GET", "/health", nil)
+		req.Header.Set("Origin", "http://example.com")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, "http://example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Nil(t, r.Shutdown(context.Background()))
+	})
+}
+
+func TestApiRouter_Serve(t *testing.T) {
+	t.Parallel()
+
+	t.Run("it returns the listener error", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := NewApiRouter(&ApiRouterConfig{Handler: chi.NewRouter(), Addr: "127.0.0.1:-1"})
+		if err != nil {
+			t.FailNow()
+		}
+
+		if r.Init() != nil {
+			t.FailNow()
+		}
+
+		err = r.Serve(context.Background())
+
+		assert.Error(t, err)
+	})
+
+	t.Run("it does not start serving after Shutdown", func(t *testing.T) {
+		t.Parallel()
+
+		addr := freeAddr(t)
+
+		r, err := NewApiRouter(&ApiRouterConfig{Handler: chi.NewRouter(), Addr: addr})
+		if err != nil {
+			t.FailNow()
+		}
+
+		if r.Init() != nil {
+			t.FailNow()
+		}
+
+		assert.Nil(t, r.Shutdown(context.Background()))
+
+		err = r.Serve(context.Background())
+
+		assert.ErrorIs(t, err, http.ErrServerClosed)
+
+		_, err = net.Dial("tcp", addr)
+		assert.Error(t, err)
+	})
+
+	t.Run("it lets in-flight requests finish once the context ends", func(t *testing.T) {
+		t.Parallel()
+
+		addr := freeAddr(t)
+		handler := chi.NewRouter()
+
+		r, err := NewApiRouter(&ApiRouterConfig{Handler: handler, Addr: addr, ShutdownGracePeriod: 5 * time.Second})
+		if err != nil {
+			t.FailNow()
+		}
+
+		if r.Init() != nil {
+			t.FailNow()
+		}
+
+		started := make(chan struct{})
+		handler.Get("/slow", func(w http.ResponseWriter, req *http.Request) {
+			close(started)
+			time.Sleep(100 * time.Millisecond)
+			_, _ = io.WriteString(w, "done")
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		served := make(chan error, 1)
+		go func() {
+			served <- r.Serve(ctx)
+		}()
+
+		waitForServer(t, addr)
+
+		type result struct {
+			body string
+			err  error
+		}
+		responses := make(chan result, 1)
+		go func() {
+			resp, err := http.Get("http://" + addr + "/slow")
+			if err != nil {
+				responses <- result{err: err}
+				return
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			responses <- result{body: string(body), err: err}
+		}()
+
+		<-started
+		cancel()
+
+		response := <-responses
+
+		assert.Nil(t, response.err)
+		assert.Equal(t, "done", response.body)
+		assert.Nil(t, <-served)
+
+		_, err = net.Dial("tcp", addr)
+		assert.Error(t, err)
+	})
+
+	t.Run("it cuts off requests that outlast the grace period", func(t *testing.T) {
+		t.Parallel()
+
+		addr := freeAddr(t)
+		handler := chi.NewRouter()
+
+		r, err := NewApiRouter(&ApiRouterConfig{Handler: handler, Addr: addr, ShutdownGracePeriod: 50 * time.Millisecond})
+		if err != nil {
+			t.FailNow()
+		}
+
+		if r.Init() != nil {
+			t.FailNow()
+		}
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+
+		handler.Get("/stuck", func(w http.ResponseWriter, req *http.Request) {
+			close(started)
+			<-release
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		served := make(chan error, 1)
+		go func() {
+			served <- r.Serve(ctx)
+		}()
+
+		waitForServer(t, addr)
+
+		go func() {
+			resp, err := http.Get("http://" + addr + "/stuck")
+			if err == nil {
+				resp.Body.Close()
+			}
+		}()
+
+		<-started
+		cancel()
+
+		assert.ErrorIs(t, <-served, context.DeadlineExceeded)
+	})
+}
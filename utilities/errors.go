@@ -0,0 +1,74 @@
+package utilities
+
+import "net/http"
+
+// ErrorCode is an application error code, reported as "code" in every error
+// response so clients can tell apart failures that share an HTTP status.
+// Codes are grouped by hundreds: 1xxx request errors, 2xxx lookup errors,
+// 3xxx upstream errors and 9xxx internal errors.
+type ErrorCode int64
+
+const (
+	CodeBadRequest        ErrorCode = 1000
+	CodeInvalidParameters ErrorCode = 1001
+	CodeMissingParameter  ErrorCode = 1002
+	CodeNotAcceptable     ErrorCode = 1003
+
+	CodeNotFound ErrorCode = 2000
+
+	CodeUpstreamRejected    ErrorCode = 3000
+	CodeUpstreamRateLimited ErrorCode = 3001
+	CodeUpstreamUnavailable ErrorCode = 3002
+	CodeUpstreamTimeout     ErrorCode = 3003
+	CodeUpstreamMalformed   ErrorCode = 3004
+	CodeCircuitOpen         ErrorCode = 3005
+
+	CodeInternal ErrorCode = 9000
+)
+
+type errorCodeInfo struct {
+	status int
+	slug   string
+	title  string
+}
+
+var errorCatalog = map[ErrorCode]errorCodeInfo{
+	CodeBadRequest:          {http.StatusBadRequest, "bad-request", "The request could not be understood"},
+	CodeInvalidParameters:   {http.StatusBadRequest, "invalid-parameters", "One or more parameters are invalid"},
+	CodeMissingParameter:    {http.StatusBadRequest, "missing-parameter", "A required parameter is missing"},
+	CodeNotAcceptable:       {http.StatusNotAcceptable, "not-acceptable", "None of the accepted media types can be produced"},
+	CodeNotFound:            {http.StatusNotFound, "not-found", "The requested resource does not exist"},
+	CodeUpstreamRejected:    {http.StatusBadRequest, "upstream-rejected", "The upstream API rejected the request"},
+	CodeUpstreamRateLimited: {http.StatusTooManyRequests, "upstream-rate-limited", "Too many requests to the upstream API"},
+	CodeUpstreamUnavailable: {http.StatusBadGateway, "upstream-unavailable", "The upstream API could not be reached"},
+	CodeUpstreamTimeout:     {http.StatusGatewayTimeout, "upstream-timeout", "The upstream API did not answer in time"},
+	CodeUpstreamMalformed:   {http.StatusBadGateway, "upstream-malformed", "The upstream API sent a malformed response"},
+	CodeCircuitOpen:         {http.StatusServiceUnavailable, "circuit-open", "The upstream API is temporarily disabled after repeated failures"},
+	CodeInternal:            {http.StatusInternalServerError, "internal", "An unexpected error occurred"},
+}
+
+// Status is the HTTP status code responses carrying c are sent with. Codes
+// missing from the catalog are treated as internal errors.
+func (c ErrorCode) Status() int {
+	return c.info().status
+}
+
+// Title is a short, human-readable summary of c that does not change between
+// occurrences.
+func (c ErrorCode) Title() string {
+	return c.info().title
+}
+
+// Type is the problem type URI identifying c in problem+json documents.
+func (c ErrorCode) Type() string {
+	return "urn:gojo:problem:" + c.info().slug
+}
+
+func (c ErrorCode) info() errorCodeInfo {
+	info, ok := errorCatalog[c]
+	if !ok {
+		return errorCatalog[CodeInternal]
+	}
+
+	return info
+}
@@ -0,0 +1,59 @@
+package utilities
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HttpClient is the subset of *http.Client used to talk to upstream APIs.
+type HttpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type HttpClientConfig struct {
+	Timeout             time.Duration // Overall time limit per request, including reading the body.
+	ProxyURL            string        // Falls back to the HTTP_PROXY/HTTPS_PROXY environment when empty.
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	TLSHandshakeTimeout time.Duration
+}
+
+func NewHttpClient(cfg *HttpClientConfig) (*http.Client, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("missing config parameter")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ProxyURL parameter: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if cfg.MaxIdleConns > 0 {
+		transport.MaxIdleConns = cfg.MaxIdleConns
+	}
+
+	if cfg.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+
+	if cfg.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = cfg.IdleConnTimeout
+	}
+
+	if cfg.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = cfg.TLSHandshakeTimeout
+	}
+
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: transport,
+	}, nil
+}
@@ -0,0 +1,73 @@
+package utilities
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+const ContentTypeProblemJSON = "application/problem+json"
+
+// Problem is an RFC 7807 problem details document. Code, InvalidParams and
+// RequestID are extension members.
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	Code          ErrorCode      `json:"code"`
+	InvalidParams []InvalidParam `json:"invalid_params,omitempty"`
+	RequestID     string         `json:"request_id,omitempty"`
+}
+
+type problemDetailsKey struct{}
+
+// ProblemDetails is a middleware that makes every error rendered through
+// this package a problem+json document. Without it, only requests that
+// Accept application/problem+json get one.
+func ProblemDetails(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), problemDetailsKey{}, true)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func wantsProblem(r *http.Request) bool {
+	if enabled, _ := r.Context().Value(problemDetailsKey{}).(bool); enabled {
+		return true
+	}
+
+	return strings.Contains(r.Header.Get("Accept"), ContentTypeProblemJSON)
+}
+
+func newProblem(r *http.Request, code ErrorCode, err error, params []InvalidParam) Problem {
+	return Problem{
+		Type:          code.Type(),
+		Title:         code.Title(),
+		Status:        code.Status(),
+		Detail:        err.Error(),
+		Instance:      r.URL.RequestURI(),
+		Code:          code,
+		InvalidParams: params,
+		RequestID:     middleware.GetReqID(r.Context()),
+	}
+}
+
+// renderProblem writes p by hand, since render.JSON would overwrite the
+// problem+json content type.
+func renderProblem(w http.ResponseWriter, p Problem) {
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(p); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentTypeProblemJSON)
+	w.WriteHeader(p.Status)
+	_, _ = w.Write(buf.Bytes())
+}
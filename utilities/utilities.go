@@ -1,43 +1,51 @@
 package utilities
 
 import (
-	"errors"
 	"net/http"
 
 	"github.com/go-chi/render"
 )
 
 type ErrorResponse struct {
-	StatusText string `json:"status"`
-	AppCode    int64  `json:"code,omitempty"`
-	ErrorText  string `json:"error"`
+	StatusText    string         `json:"status"`
+	AppCode       int64          `json:"code,omitempty"`
+	ErrorText     string         `json:"error"`
+	InvalidParams []InvalidParam `json:"invalid_params,omitempty"`
 }
 
-func RenderHTTPError(w http.ResponseWriter, r *http.Request) {
-	jsonError(w, r, 400, errors.New(http.StatusText(400)))
+// InvalidParam describes one rejected request parameter, or one rejected
+// token of a list-valued parameter.
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Value  string `json:"value,omitempty"`
+	Reason string `json:"reason"`
 }
 
-func RenderAuthError(w http.ResponseWriter, r *http.Request) {
-	jsonError(w, r, 401, errors.New(http.StatusText(401)))
+// RenderError renders err with the HTTP status of code, as a problem+json
+// document when the request asks for one and as an ErrorResponse otherwise.
+func RenderError(w http.ResponseWriter, r *http.Request, code ErrorCode, err error) {
+	renderError(w, r, code, err, nil)
 }
 
-func RenderNotAllowedError(w http.ResponseWriter, r *http.Request) {
-	jsonError(w, r, 405, errors.New(http.StatusText(405)))
+// RenderInvalidParamsError renders a 400 that lists every rejected parameter
+// alongside the summary in err.
+func RenderInvalidParamsError(w http.ResponseWriter, r *http.Request, err error, params []InvalidParam) {
+	renderError(w, r, CodeInvalidParameters, err, params)
 }
 
-func RenderUnprocessableError(w http.ResponseWriter, r *http.Request) {
-	jsonError(w, r, 422, errors.New(http.StatusText(422)))
-}
-
-func RenderServerError(w http.ResponseWriter, r *http.Request, err error) {
-	jsonError(w, r, 500, err)
-}
+func renderError(w http.ResponseWriter, r *http.Request, code ErrorCode, err error, params []InvalidParam) {
+	if wantsProblem(r) {
+		renderProblem(w, newProblem(r, code, err, params))
+		return
+	}
 
-func jsonError(w http.ResponseWriter, r *http.Request, code int, err error) {
-	w.WriteHeader(code)
+	status := code.Status()
 
+	render.Status(r, status)
 	render.JSON(w, r, ErrorResponse{
-		StatusText: http.StatusText(code),
-		ErrorText:  err.Error(),
+		StatusText:    http.StatusText(status),
+		AppCode:       int64(code),
+		ErrorText:     err.Error(),
+		InvalidParams: params,
 	})
 }